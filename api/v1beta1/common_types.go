@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // KubernetesConfig will define the Kubernetes specific properties
@@ -28,6 +29,22 @@ type KubernetesConfig struct {
 	Tolerations       *[]corev1.Toleration         `json:"tolerations,omitempty"`
 	PriorityClassName *string                      `json:"priorityClassName,omitempty"`
 	SecurityContext   *corev1.PodSecurityContext   `json:"securityContext,omitempty"`
+	// ContainerSecurityContext is applied to all containers of the pods except the privileged sysctl init container
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// Image overrides the default image, a reference without tag is tagged with the version of the cluster
+	Image string `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy  corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// PodDisruptionBudget defines how many pods may be evicted at once, e.g. while nodes are drained
+type PodDisruptionBudget struct {
+	// Enabled creates the budget, it is enabled by default
+	Enabled *bool `json:"enabled,omitempty"`
+	// MinAvailable and MaxUnavailable replace the defaults of the operator, only one of them can be set
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // Storage is the inteface to add pvc and pv support in MongoDB
@@ -36,3 +53,20 @@ type Storage struct {
 	StorageClassName *string                             `json:"storageClass,omitempty" protobuf:"bytes,5,opt,name=storageClassName"`
 	StorageSize      string                              `json:"storageSize,omitempty" protobuf:"bytes,5,opt,name=storageClassName"`
 }
+
+// ElasticsearchRef is the reference to an Elasticsearch cluster managed in the same namespace
+type ElasticsearchRef struct {
+	Name string `json:"name"`
+}
+
+// Condition types which are reported in the status of every custom resource
+const (
+	// ConditionReady is True once the desired state of the spec is available
+	ConditionReady = "Ready"
+	// ConditionProgressing is True while a change of the spec is being rolled out
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is True while the resource is running with reduced availability
+	ConditionDegraded = "Degraded"
+	// ConditionReconciled is False if the last reconcile stopped with an error
+	ConditionReconciled = "Reconciled"
+)
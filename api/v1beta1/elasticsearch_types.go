@@ -17,26 +17,135 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
-// +kubebuilder:object:root=true
-
 // ElasticsearchSpec defines the desired state of Elasticsearch
 type ElasticsearchSpec struct {
 	ClusterName string    `json:"esClusterName"`
 	ESVersion   string    `json:"esVersion"`
 	Security    *Security `json:"esSecurity,omitempty"`
-	// +kubebuilder:validation:default:={esMaster:{replicas: 3}}
-	// +kubebuilder:default:={storage:{accessModes: {ReadWriteOnce}, storageSize: "1Gi"},jvmMaxMemory: "1g", jvmMinMemory: "1g", replicas: 3}
-	ESMaster         *NodeSpecificConfig `json:"esMaster,omitempty"`
-	ESData           *NodeSpecificConfig `json:"esData,omitempty"`
-	ESIngestion      *NodeSpecificConfig `json:"esIngestion,omitempty"`
-	ESClient         *NodeSpecificConfig `json:"esClient,omitempty"`
-	ESPlugins        *[]string           `json:"esPlugins,omitempty"`
-	ESKeystoreSecret *string             `json:"esKeystoreSecret,omitempty"`
+	// ESMaster is a shorthand for the node set master, a cluster needs it or a node set with the master role
+	ESMaster    *NodeSpecificConfig `json:"esMaster,omitempty"`
+	ESData      *NodeSpecificConfig `json:"esData,omitempty"`
+	ESIngestion *NodeSpecificConfig `json:"esIngestion,omitempty"`
+	ESClient    *NodeSpecificConfig `json:"esClient,omitempty"`
+	// ESPlugins are plugin names or URLs of plugin archives
+	ESPlugins *[]string `json:"esPlugins,omitempty"`
+	// UnverifiedPlugins lists entries of esPlugins which are installed when no .sha512 checksum is published for their archive
+	UnverifiedPlugins []string `json:"unverifiedPlugins,omitempty"`
+	// ESKeystoreSecret adds every key of the secret to the keystore as a secure setting of the same name
+	ESKeystoreSecret *string `json:"esKeystoreSecret,omitempty"`
+	// Keystore lists further secrets whose keys are added to the keystore, reloadable settings are reloaded without restart
+	Keystore []KeystoreSecret `json:"keystore,omitempty"`
+	// PluginSource replaces the elastic artifacts service as the source of plugins given by name
+	PluginSource *PluginSource `json:"pluginSource,omitempty"`
+	// NodeSets are additional groups of nodes, esMaster, esData, esIngestion and esClient are converted into node sets
+	// +listType=map
+	// +listMapKey=name
+	NodeSets []NodeSet `json:"nodeSets,omitempty"`
+	// ZoneAwareness spreads nodes across zones and makes shard allocation zone aware
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+	// RestoreFrom restores a snapshot once after the cluster has been created
+	RestoreFrom *RestoreFrom `json:"restoreFrom,omitempty"`
+	// VolumeClaimDeletePolicy defines if persistent volume claims are deleted on scale down and cluster deletion
+	// +kubebuilder:validation:Enum=Retain;DeleteOnScaledownOnly;DeleteOnScaledownAndClusterDeletion
+	// +kubebuilder:default:=Retain
+	VolumeClaimDeletePolicy string `json:"volumeClaimDeletePolicy,omitempty"`
+	// FinalSnapshot is taken before the cluster is deleted
+	FinalSnapshot *FinalSnapshot `json:"finalSnapshot,omitempty"`
+	// VMMaxMapCount defines how the vm.max_map_count kernel setting required by elasticsearch is raised
+	VMMaxMapCount *VMMaxMapCount `json:"vmMaxMapCount,omitempty"`
+	// ClusterSettings are applied as persistent cluster settings, e.g. indices.recovery.max_bytes_per_sec: 100mb
+	ClusterSettings map[string]string `json:"clusterSettings,omitempty"`
+}
+
+// VMMaxMapCount defines how vm.max_map_count is raised on the nodes which run elasticsearch
+type VMMaxMapCount struct {
+	// Mode InitContainer raises it with a privileged init container, DaemonSet with a privileged daemonset,
+	// DisableMmap sets node.store.allow_mmap to false and None expects it to be raised on the nodes already
+	// +kubebuilder:validation:Enum=InitContainer;DaemonSet;DisableMmap;None
+	// +kubebuilder:default:=InitContainer
+	Mode string `json:"mode,omitempty"`
+	// DaemonSetNamespace has to allow privileged pods, the daemonset runs in the namespace of the cluster by default
+	DaemonSetNamespace string `json:"daemonSetNamespace,omitempty"`
+}
+
+// KeystoreSecret defines a secret whose keys are added to the keystore
+type KeystoreSecret struct {
+	SecretName string `json:"secretName"`
+	// Items select and remap keys of the secret, every key is added as a setting of the same name when it is empty
+	Items []KeystoreItem `json:"items,omitempty"`
+}
+
+// KeystoreItem maps a key of a secret to a secure setting
+type KeystoreItem struct {
+	Key string `json:"key"`
+	// Setting is the name of the secure setting, e.g. s3.client.default.access_key, it defaults to the key
+	Setting string `json:"setting,omitempty"`
+}
+
+// PluginSource defines where plugins are installed from, e.g. for air-gapped clusters
+type PluginSource struct {
+	// MirrorURL serves <plugin>/<plugin>-<version>.zip in the layout of artifacts.elastic.co/downloads/elasticsearch-plugins
+	MirrorURL string `json:"mirrorURL,omitempty"`
+	// Volume holds <plugin>-<version>.zip archives in Path, e.g. a persistent volume claim
+	Volume *corev1.VolumeSource `json:"volume,omitempty"`
+	// Image holds <plugin>-<version>.zip archives in Path, they are copied into the pods by an init container
+	Image string `json:"image,omitempty"`
+	// Path is the directory of the archives in the volume or image
+	// +kubebuilder:default:=/plugins
+	Path string `json:"path,omitempty"`
+}
+
+// FinalSnapshot defines the snapshot which is taken before the cluster is deleted
+type FinalSnapshot struct {
+	// Repository is the name of a SnapshotRepository which references this cluster
+	Repository string   `json:"repository"`
+	Indices    []string `json:"indices,omitempty"`
+}
+
+// RestoreFrom defines the snapshot which is restored into a new cluster
+type RestoreFrom struct {
+	// Repository is the name of a SnapshotRepository which references this cluster
+	Repository string `json:"repository"`
+	Snapshot   string `json:"snapshot"`
+	// Indices to restore, all indices of the snapshot are restored when empty
+	Indices            []string `json:"indices,omitempty"`
+	IncludeGlobalState *bool    `json:"includeGlobalState,omitempty"`
 }
 
+// ZoneAwareness defines how elasticsearch nodes are spread across zones
+type ZoneAwareness struct {
+	// TopologyKey is the node label which contains the zone
+	// +kubebuilder:default:=topology.kubernetes.io/zone
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// Zones enables forced awareness, shard copies are never allocated twice in the same zone when a zone is lost
+	Zones []string `json:"zones,omitempty"`
+	// +kubebuilder:default:=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +kubebuilder:default:=DoNotSchedule
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// NodeSet defines a group of elasticsearch nodes sharing the same roles and configuration
+type NodeSet struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Roles is the list of node.roles, an empty list creates coordinating only nodes
+	Roles []NodeRole `json:"roles,omitempty"`
+	// NodeAttributes are set as node.attr.<key> and can be used for shard allocation filtering
+	NodeAttributes     map[string]string `json:"nodeAttributes,omitempty"`
+	NodeSpecificConfig `json:",inline"`
+}
+
+// NodeRole is a role of an elasticsearch node
+// +kubebuilder:validation:Enum=master;voting_only;data;data_content;data_hot;data_warm;data_cold;data_frozen;ingest;ml;remote_cluster_client;transform
+type NodeRole string
+
 // NodeSpecificConfig defines the properties for elasticsearch nodes
 type NodeSpecificConfig struct {
 	KubernetesConfig *KubernetesConfig `json:"kubernetesConfig,omitempty"`
@@ -45,6 +154,11 @@ type NodeSpecificConfig struct {
 	Storage          *Storage          `json:"storage,omitempty"`
 	JvmMaxMemory     *string           `json:"jvmMaxMemory,omitempty"`
 	JvmMinMemory     *string           `json:"jvmMinMemory,omitempty"`
+	// Config is merged into the elasticsearch.yml of the nodes, it supports nested objects and lists
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *runtime.RawExtension `json:"config,omitempty"`
+	// PodDisruptionBudget overrides the budget which is derived from the roles of the node set
+	PodDisruptionBudget *PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
 }
 
 // Security defines the security config of Elasticsearch
@@ -52,31 +166,229 @@ type Security struct {
 	ExistingSecret       *string `json:"existingSecret,omitempty"`
 	TLSEnabled           *bool   `json:"tlsEnabled,omitempty"`
 	AutoGeneratePassword *bool   `json:"autoGeneratePassword,omitempty"`
+	// CertificateRenewBefore is the window before expiry in which operator generated certificates are renewed
+	// It has to be shorter than half of the validity of node certificates, which is one year
+	CertificateRenewBefore *metav1.Duration `json:"certificateRenewBefore,omitempty"`
+	// IssuerRef is the cert-manager issuer which signs the certificates instead of the operator
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+	// HTTP overrides the TLS configuration of the REST endpoint
+	HTTP *TLSLayerConfig `json:"http,omitempty"`
+	// Transport overrides the TLS configuration of node to node traffic
+	Transport *TLSLayerConfig `json:"transport,omitempty"`
+}
+
+// TLSLayerConfig defines the TLS configuration of the http or transport layer
+type TLSLayerConfig struct {
+	// Enabled toggles TLS for the layer, it defaults to tlsEnabled
+	Enabled *bool `json:"enabled,omitempty"`
+	// SecretName is a user provided secret with tls.crt, tls.key and ca.crt for PEM or keystore.p12 for PKCS12
+	SecretName *string `json:"secretName,omitempty"`
+	// +kubebuilder:validation:Enum=PEM;PKCS12
+	// +kubebuilder:default:=PEM
+	Format string `json:"format,omitempty"`
+	// PasswordSecret is the password of the PEM private key or the PKCS12 keystore
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
+}
+
+// IsHTTPTLSEnabled is a method to check if the elasticsearch REST endpoint is served over TLS
+func (s *Security) IsHTTPTLSEnabled() bool {
+	if s == nil {
+		return false
+	}
+	return s.isLayerEnabled(s.HTTP)
+}
+
+// IsTransportTLSEnabled is a method to check if elasticsearch nodes talk to each other over TLS
+func (s *Security) IsTransportTLSEnabled() bool {
+	if s == nil {
+		return false
+	}
+	return s.isLayerEnabled(s.Transport)
+}
+
+// isLayerEnabled is a method to resolve the TLS toggle of a layer
+func (s *Security) isLayerEnabled(layer *TLSLayerConfig) bool {
+	if layer != nil && layer.Enabled != nil {
+		return *layer.Enabled
+	}
+	return s.TLSEnabled != nil && *s.TLSEnabled
+}
+
+// IssuerReference defines the cert-manager issuer used for certificates
+type IssuerReference struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default:=Issuer
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:default:=cert-manager.io
+	Group string `json:"group,omitempty"`
 }
 
-//+kubebuilder:subresource:status
 // ElasticsearchStatus defines the observed state of Elasticsearch
 type ElasticsearchStatus struct {
-	ESVersion    string `json:"esVersion,omitempty"`
-	ClusterState string `json:"esClusterState,omitempty"`
-	ActiveShards *int32 `json:"activeShards,omitempty"`
-	Indices      *int32 `json:"indices,omitempty"`
-	ESMaster     *int32 `json:"esMaster,omitempty"`
-	ESData       *int32 `json:"esData,omitempty"`
-	ESClient     *int32 `json:"esClient,omitempty"`
-	ESIngestion  *int32 `json:"esIngestion,omitempty"`
+	ESVersion    string           `json:"esVersion,omitempty"`
+	ClusterState string           `json:"esClusterState,omitempty"`
+	ActiveShards *int32           `json:"activeShards,omitempty"`
+	Indices      *int32           `json:"indices,omitempty"`
+	ESMaster     *int32           `json:"esMaster,omitempty"`
+	ESData       *int32           `json:"esData,omitempty"`
+	ESClient     *int32           `json:"esClient,omitempty"`
+	ESIngestion  *int32           `json:"esIngestion,omitempty"`
+	TLS          *TLSStatus       `json:"tls,omitempty"`
+	Upgrade      *UpgradeStatus   `json:"upgrade,omitempty"`
+	ScaleDown    *ScaleDownStatus `json:"scaleDown,omitempty"`
+	DataTiers    []DataTierStatus `json:"dataTiers,omitempty"`
+	// DataTierWarnings lists node set configurations which do not line up with tier routing
+	DataTierWarnings []string `json:"dataTierWarnings,omitempty"`
+	// VolumeExpansion lists the persistent volume claims which are being resized
+	VolumeExpansion []VolumeExpansionStatus `json:"volumeExpansion,omitempty"`
+	Restore         *RestoreStatus          `json:"restore,omitempty"`
+	FinalSnapshot   *FinalSnapshotStatus    `json:"finalSnapshot,omitempty"`
+	// Shards counts the shards of the cluster by their state
+	Shards *ShardStatus `json:"shards,omitempty"`
+	// NodesPerRole counts the nodes which joined the cluster for every node role
+	NodesPerRole map[string]int32 `json:"nodesPerRole,omitempty"`
+	// Nodes lists the running version, heap and disk usage of every node which joined the cluster
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+	// Plugins lists the plugins which are installed on the nodes which joined the cluster
+	Plugins []PluginStatus `json:"plugins,omitempty"`
+	// Keystore reports the secure settings which were reloaded last
+	Keystore *KeystoreStatus `json:"keystore,omitempty"`
+	// RestrictedDisruptionBudgets lists the node sets whose disruption budget allows no evictions until the cluster is green
+	RestrictedDisruptionBudgets []string `json:"restrictedDisruptionBudgets,omitempty"`
+	// AppliedClusterSettings lists the persistent cluster settings which were set by the operator
+	AppliedClusterSettings []string `json:"appliedClusterSettings,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// KeystoreStatus defines the rebuild and reload progress of the keystores of the running pods
+type KeystoreStatus struct {
+	// ReloadedHash is the hash of the secure settings which were reloaded last
+	ReloadedHash string       `json:"reloadedHash,omitempty"`
+	ReloadTime   *metav1.Time `json:"reloadTime,omitempty"`
+	// PendingPods did not rebuild their keystore from the current secure settings yet
+	PendingPods []string `json:"pendingPods,omitempty"`
+}
+
+// PluginStatus defines an installed plugin and the number of nodes it is installed on
+type PluginStatus struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Nodes   int32  `json:"nodes"`
+}
+
+// ShardStatus defines the number of shards in every allocation state
+type ShardStatus struct {
+	Active       int32 `json:"active"`
+	Primary      int32 `json:"primary"`
+	Relocating   int32 `json:"relocating"`
+	Initializing int32 `json:"initializing"`
+	Unassigned   int32 `json:"unassigned"`
+}
+
+// NodeStatus defines the observed state of an elasticsearch node
+type NodeStatus struct {
+	Name            string   `json:"name"`
+	Roles           []string `json:"roles,omitempty"`
+	Master          bool     `json:"master,omitempty"`
+	Version         string   `json:"version,omitempty"`
+	HeapUsedPercent int32    `json:"heapUsedPercent"`
+	HeapMaxBytes    int64    `json:"heapMaxBytes,omitempty"`
+	DiskUsedPercent int32    `json:"diskUsedPercent"`
+	DiskUsedBytes   int64    `json:"diskUsedBytes,omitempty"`
+	DiskTotalBytes  int64    `json:"diskTotalBytes,omitempty"`
+	Shards          int32    `json:"shards"`
+}
+
+// FinalSnapshotStatus defines the progress of the snapshot which is taken before the cluster is deleted
+type FinalSnapshotStatus struct {
+	Repository string `json:"repository,omitempty"`
+	Name       string `json:"name,omitempty"`
+	State      string `json:"state,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// RestoreStatus defines the progress of restoring a snapshot, a completed restore is never run again
+type RestoreStatus struct {
+	Repository      string       `json:"repository,omitempty"`
+	Snapshot        string       `json:"snapshot,omitempty"`
+	Phase           string       `json:"phase,omitempty"`
+	Message         string       `json:"message,omitempty"`
+	StartTime       *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time `json:"completionTime,omitempty"`
+	TotalShards     int32        `json:"totalShards,omitempty"`
+	RecoveredShards int32        `json:"recoveredShards,omitempty"`
+	TotalBytes      int64        `json:"totalBytes,omitempty"`
+	RecoveredBytes  int64        `json:"recoveredBytes,omitempty"`
+}
+
+// VolumeExpansionStatus defines the resize progress of the volume of a pod
+type VolumeExpansionStatus struct {
+	Pod           string `json:"pod"`
+	Claim         string `json:"claim"`
+	RequestedSize string `json:"requestedSize,omitempty"`
+	CurrentSize   string `json:"currentSize,omitempty"`
+	Phase         string `json:"phase,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// DataTierStatus defines the nodes and disk usage of a data tier
+type DataTierStatus struct {
+	Tier            string `json:"tier"`
+	Nodes           int32  `json:"nodes"`
+	DiskTotalBytes  int64  `json:"diskTotalBytes,omitempty"`
+	DiskUsedBytes   int64  `json:"diskUsedBytes,omitempty"`
+	DiskUsedPercent int32  `json:"diskUsedPercent,omitempty"`
+}
+
+// ScaleDownStatus defines the progress of removing nodes from the cluster
+type ScaleDownStatus struct {
+	Role            string   `json:"role,omitempty"`
+	Nodes           []string `json:"nodes,omitempty"`
+	Phase           string   `json:"phase,omitempty"`
+	RemainingShards int32    `json:"remainingShards,omitempty"`
+}
+
+// UpgradeStatus defines the progress of a rolling version upgrade
+type UpgradeStatus struct {
+	FromVersion   string `json:"fromVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+	Phase         string `json:"phase,omitempty"`
+	Message       string `json:"message,omitempty"`
+	// CurrentRole and Partition define the statefulset partition which was released last
+	CurrentRole   string `json:"currentRole,omitempty"`
+	Partition     int32  `json:"partition,omitempty"`
+	CurrentNode   string `json:"currentNode,omitempty"`
+	UpgradedNodes int32  `json:"upgradedNodes,omitempty"`
+	TotalNodes    int32  `json:"totalNodes,omitempty"`
+}
+
+// TLSStatus defines the observed state of operator generated certificates
+type TLSStatus struct {
+	NotAfter      *metav1.Time `json:"notAfter,omitempty"`
+	CANotAfter    *metav1.Time `json:"caNotAfter,omitempty"`
+	RotationPhase string       `json:"rotationPhase,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Version",type=string,priority=0,JSONPath=`.status.esVersion`
 // +kubebuilder:printcolumn:name="State",type=string,priority=0,JSONPath=`.status.esClusterState`
 // +kubebuilder:printcolumn:name="Shards",type=integer,priority=0,JSONPath=`.status.activeShards`
 // +kubebuilder:printcolumn:name="Indices",type=integer,priority=0,JSONPath=`.status.indices`
+// +kubebuilder:printcolumn:name="Unassigned",type=integer,priority=1,JSONPath=`.status.shards.unassigned`
 // +kubebuilder:printcolumn:name="Master",type=integer,priority=1,JSONPath=`.status.esMaster`
-// +kubebuilder:printcolumn:name="Data",type=integer,priority=1,JSONPath=`.status.esClient`
-// +kubebuilder:printcolumn:name="Client",type=integer,priority=1,JSONPath=`.status.esMaster`
+// +kubebuilder:printcolumn:name="Data",type=integer,priority=1,JSONPath=`.status.esData`
+// +kubebuilder:printcolumn:name="Client",type=integer,priority=1,JSONPath=`.status.esClient`
 // +kubebuilder:printcolumn:name="Ingestion",type=integer,priority=1,JSONPath=`.status.esIngestion`
+// +kubebuilder:printcolumn:name="Upgrade",type=string,priority=1,JSONPath=`.status.upgrade.phase`
+// +kubebuilder:printcolumn:name="Cert Expiry",type=string,format=date-time,priority=1,JSONPath=`.status.tls.notAfter`
 // Elasticsearch is the Schema for the elasticsearches API
 type Elasticsearch struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchAPIKeySpec defines the desired state of ElasticsearchAPIKey
+type ElasticsearchAPIKeySpec struct {
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+	// RoleDescriptors limit the privileges of the key
+	// +kubebuilder:validation:MinProperties=1
+	RoleDescriptors map[string]RoleDescriptor `json:"roleDescriptors"`
+	// Expiration is the lifetime of a key in elasticsearch time units, e.g. 30d, keys do not expire when it is empty
+	Expiration string `json:"expiration,omitempty"`
+	// RotationInterval is the age after which a new key is issued, e.g. 168h
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+	// GracePeriod is the time for which a rotated key stays valid
+	// +kubebuilder:default:="1h"
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	// SecretName is the secret which receives the key, it defaults to <name>-api-key
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// APIKeyStatus defines a key which was issued in elasticsearch
+type APIKeyStatus struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name,omitempty"`
+	CreationTime metav1.Time  `json:"creationTime,omitempty"`
+	Expiration   *metav1.Time `json:"expiration,omitempty"`
+	// InvalidateAfter is set on rotated keys which are invalidated after the grace period
+	InvalidateAfter *metav1.Time `json:"invalidateAfter,omitempty"`
+}
+
+// ElasticsearchAPIKeyStatus defines the observed state of ElasticsearchAPIKey
+type ElasticsearchAPIKeyStatus struct {
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message,omitempty"`
+	// SecretName is the secret which holds the current key
+	SecretName string `json:"secretName,omitempty"`
+	// CurrentKey is the key stored in the secret
+	CurrentKey *APIKeyStatus `json:"currentKey,omitempty"`
+	// RotatedKeys are replaced keys which are still valid during the grace period
+	RotatedKeys []APIKeyStatus `json:"rotatedKeys,omitempty"`
+	// KeyGeneration is the generation of the spec which the current key was issued for
+	KeyGeneration int64 `json:"keyGeneration,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Es Cluster",type=string,priority=0,JSONPath=`.spec.elasticsearchRef.name`
+// +kubebuilder:printcolumn:name="Secret",type=string,priority=0,JSONPath=`.status.secretName`
+// +kubebuilder:printcolumn:name="Key ID",type=string,priority=0,JSONPath=`.status.currentKey.id`
+// +kubebuilder:printcolumn:name="Expiration",type=date,priority=0,JSONPath=`.status.currentKey.expiration`
+// +kubebuilder:printcolumn:name="Phase",type=string,priority=0,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// ElasticsearchAPIKey is the Schema for the elasticsearchapikeys API
+type ElasticsearchAPIKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchAPIKeySpec   `json:"spec,omitempty"`
+	Status ElasticsearchAPIKeyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ElasticsearchAPIKeyList contains a list of ElasticsearchAPIKey
+type ElasticsearchAPIKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchAPIKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchAPIKey{}, &ElasticsearchAPIKeyList{})
+}
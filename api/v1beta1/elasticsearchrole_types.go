@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchRoleSpec defines the desired state of ElasticsearchRole
+type ElasticsearchRoleSpec struct {
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+	RoleDescriptor   `json:",inline"`
+}
+
+// RoleDescriptor defines the privileges of a role or an API key
+type RoleDescriptor struct {
+	// Cluster privileges, e.g. monitor or manage_index_templates
+	Cluster      []string                `json:"cluster,omitempty"`
+	Indices      []IndexPrivileges       `json:"indices,omitempty"`
+	Applications []ApplicationPrivileges `json:"applications,omitempty"`
+	// RunAs lists the users which the owners of the role can impersonate
+	RunAs []string `json:"runAs,omitempty"`
+}
+
+// IndexPrivileges defines the privileges of a role on a group of indices
+type IndexPrivileges struct {
+	Names      []string `json:"names"`
+	Privileges []string `json:"privileges"`
+	// FieldSecurity restricts the fields which can be read from the documents
+	FieldSecurity *FieldSecurity `json:"fieldSecurity,omitempty"`
+	// Query is a search query in JSON which restricts the documents which can be read
+	Query                  string `json:"query,omitempty"`
+	AllowRestrictedIndices bool   `json:"allowRestrictedIndices,omitempty"`
+}
+
+// FieldSecurity defines the field level security of index privileges
+type FieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
+}
+
+// ApplicationPrivileges defines the privileges of a role in an application like kibana
+type ApplicationPrivileges struct {
+	Application string   `json:"application"`
+	Privileges  []string `json:"privileges"`
+	Resources   []string `json:"resources"`
+}
+
+// ElasticsearchRoleStatus defines the observed state of ElasticsearchRole
+type ElasticsearchRoleStatus struct {
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Es Cluster",type=string,priority=0,JSONPath=`.spec.elasticsearchRef.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,priority=0,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// ElasticsearchRole is the Schema for the elasticsearchroles API
+type ElasticsearchRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRoleSpec   `json:"spec,omitempty"`
+	Status ElasticsearchRoleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ElasticsearchRoleList contains a list of ElasticsearchRole
+type ElasticsearchRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRole{}, &ElasticsearchRoleList{})
+}
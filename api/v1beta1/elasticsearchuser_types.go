@@ -0,0 +1,81 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchUserSpec defines the desired state of ElasticsearchUser
+type ElasticsearchUserSpec struct {
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+	// Username defaults to the name of the resource
+	Username string `json:"username,omitempty"`
+	// Roles are built-in roles or names of ElasticsearchRole resources
+	Roles    []string `json:"roles,omitempty"`
+	FullName string   `json:"fullName,omitempty"`
+	Email    string   `json:"email,omitempty"`
+	// PasswordSecret references the password of the user, a password is generated into <name>-user-credentials when it is empty
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
+	// +kubebuilder:default:=true
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ElasticsearchUserStatus defines the observed state of ElasticsearchUser
+type ElasticsearchUserStatus struct {
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Username is the user which was created in elasticsearch
+	Username string `json:"username,omitempty"`
+	// SecretName is the secret which holds the username and password of the user
+	SecretName string `json:"secretName,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Es Cluster",type=string,priority=0,JSONPath=`.spec.elasticsearchRef.name`
+// +kubebuilder:printcolumn:name="Secret",type=string,priority=0,JSONPath=`.status.secretName`
+// +kubebuilder:printcolumn:name="Phase",type=string,priority=0,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// ElasticsearchUser is the Schema for the elasticsearchusers API
+type ElasticsearchUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchUserSpec   `json:"spec,omitempty"`
+	Status ElasticsearchUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ElasticsearchUserList contains a list of ElasticsearchUser
+type ElasticsearchUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchUser{}, &ElasticsearchUserList{})
+}
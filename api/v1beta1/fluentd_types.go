@@ -20,8 +20,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-//+kubebuilder:object:root=true
-//+kubebuilder:subresource:status
 // FluentdSpec defines the desired state of Fluentd
 type FluentdSpec struct {
 	ElasticConfig    ElasticConfig     `json:"esCluster"`
@@ -44,10 +42,17 @@ type ElasticConfig struct {
 // FluentdStatus defines the observed state of Fluentd
 type FluentdStatus struct {
 	TotalAgents *int32 `json:"totalAgents,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Elasticsearch Host",type=string,priority=0,JSONPath=`.spec.esCluster.host`
 // +kubebuilder:printcolumn:name="Total Agents",type=string,priority=0,JSONPath=`.status.totalAgents`
 // Fluentd is the Schema for the fluentds API
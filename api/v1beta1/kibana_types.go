@@ -27,14 +27,23 @@ type KibanaSpec struct {
 	ElasticConfig    ElasticConfig     `json:"esCluster"`
 	Security         *Security         `json:"esSecurity,omitempty"`
 	KubernetesConfig *KubernetesConfig `json:"kubernetesConfig,omitempty"`
+	// PodDisruptionBudget overrides the default budget which allows one unavailable pod
+	PodDisruptionBudget *PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
 }
 
 // KibanaStatus defines the observed state of Kibana
 type KibanaStatus struct {
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Version",type=string,priority=0,JSONPath=`.spec.esCluster.esVersion`
 // +kubebuilder:printcolumn:name="Es Cluster",type=string,priority=0,JSONPath=`.spec.esCluster.clusterName`
 // Kibana is the Schema for the kibanas API
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotPolicySpec defines the desired state of SnapshotPolicy
+type SnapshotPolicySpec struct {
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+	// Repository is the name of the SnapshotRepository which stores the snapshots
+	Repository string `json:"repository"`
+	// Schedule is a cron expression in the elasticsearch cron syntax
+	Schedule string `json:"schedule"`
+	// +kubebuilder:default:="<snapshot-{now/d}>"
+	SnapshotName       string             `json:"snapshotName,omitempty"`
+	Indices            []string           `json:"indices,omitempty"`
+	IgnoreUnavailable  *bool              `json:"ignoreUnavailable,omitempty"`
+	IncludeGlobalState *bool              `json:"includeGlobalState,omitempty"`
+	Retention          *SnapshotRetention `json:"retention,omitempty"`
+}
+
+// SnapshotRetention defines which snapshots are deleted by the retention task
+type SnapshotRetention struct {
+	ExpireAfter *string `json:"expireAfter,omitempty"`
+	MinCount    *int32  `json:"minCount,omitempty"`
+	MaxCount    *int32  `json:"maxCount,omitempty"`
+}
+
+// SnapshotPolicyStatus defines the observed state of SnapshotPolicy
+type SnapshotPolicyStatus struct {
+	Phase              string       `json:"phase,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastSuccess        *metav1.Time `json:"lastSuccess,omitempty"`
+	LastSuccessName    string       `json:"lastSuccessName,omitempty"`
+	LastFailure        *metav1.Time `json:"lastFailure,omitempty"`
+	LastFailureName    string       `json:"lastFailureName,omitempty"`
+	LastFailureDetails string       `json:"lastFailureDetails,omitempty"`
+	NextExecution      *metav1.Time `json:"nextExecution,omitempty"`
+	SnapshotsTaken     int64        `json:"snapshotsTaken,omitempty"`
+	SnapshotsFailed    int64        `json:"snapshotsFailed,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Repository",type=string,priority=0,JSONPath=`.spec.repository`
+// +kubebuilder:printcolumn:name="Schedule",type=string,priority=0,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Last Success",type=date,priority=0,JSONPath=`.status.lastSuccess`
+// +kubebuilder:printcolumn:name="Last Failure",type=date,priority=0,JSONPath=`.status.lastFailure`
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// SnapshotPolicy is the Schema for the snapshotpolicies API
+type SnapshotPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotPolicySpec   `json:"spec,omitempty"`
+	Status SnapshotPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SnapshotPolicyList contains a list of SnapshotPolicy
+type SnapshotPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotPolicy{}, &SnapshotPolicyList{})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotRepositorySpec defines the desired state of SnapshotRepository
+type SnapshotRepositorySpec struct {
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+	// +kubebuilder:validation:Enum=fs;s3;gcs;azure
+	Type string `json:"type"`
+	// Settings are passed as repository settings, e.g. bucket, base_path or endpoint
+	Settings map[string]string `json:"settings,omitempty"`
+	// ClientSettings are node settings of the client, e.g. endpoint, protocol or path_style_access
+	ClientSettings map[string]string `json:"clientSettings,omitempty"`
+	// CredentialsSecret keys are added to the keystore as <type>.client.<client>.<key>
+	CredentialsSecret *string `json:"credentialsSecret,omitempty"`
+	// +kubebuilder:default:=default
+	Client string `json:"client,omitempty"`
+}
+
+// SnapshotRepositoryStatus defines the observed state of SnapshotRepository
+type SnapshotRepositoryStatus struct {
+	Phase          string       `json:"phase,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	LastVerifyTime *metav1.Time `json:"lastVerifyTime,omitempty"`
+	// ObservedGeneration is the generation of the spec which was reconciled last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report if the resource is Ready, Progressing, Degraded and Reconciled
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type=string,priority=0,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Es Cluster",type=string,priority=0,JSONPath=`.spec.elasticsearchRef.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,priority=0,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,priority=0,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// SnapshotRepository is the Schema for the snapshotrepositories API
+type SnapshotRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotRepositorySpec   `json:"spec,omitempty"`
+	Status SnapshotRepositoryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SnapshotRepositoryList contains a list of SnapshotRepository
+type SnapshotRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotRepository `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotRepository{}, &SnapshotRepositoryList{})
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo/elasticsearch"
+)
+
+// Condition reasons which are shared by all controllers
+const (
+	reasonReconcileSucceeded = "ReconcileSucceeded"
+	reasonRolledOut          = "RolledOut"
+	reasonRollingOut         = "RollingOut"
+	reasonNotDegraded        = "NotDegraded"
+)
+
+// setCondition is a method to set a condition for the generation of the spec it was observed for
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status bool, reason string, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if status {
+		conditionStatus = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setReconcileFailed is a method to report the reconcile step which failed, the resource is not ready until it succeeds
+func setReconcileFailed(conditions *[]metav1.Condition, generation int64, reason string, message string) {
+	setCondition(conditions, generation, loggingv1beta1.ConditionReconciled, false, reason, message)
+	setCondition(conditions, generation, loggingv1beta1.ConditionReady, false, reason, message)
+}
+
+// setReconcileWaiting is a method to report a reconcile which stopped early to wait for a dependency
+func setReconcileWaiting(conditions *[]metav1.Condition, generation int64, reason string, message string) {
+	setCondition(conditions, generation, loggingv1beta1.ConditionReconciled, true, reasonReconcileSucceeded, "")
+	setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, true, reason, message)
+	setCondition(conditions, generation, loggingv1beta1.ConditionReady, false, reason, message)
+}
+
+// setRolloutConditions is a method to report the rollout state of the workload behind a resource
+func setRolloutConditions(conditions *[]metav1.Condition, generation int64, progressing bool, degraded bool, reason string, message string) {
+	setCondition(conditions, generation, loggingv1beta1.ConditionReconciled, true, reasonReconcileSucceeded, "")
+	switch {
+	case progressing:
+		setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, true, reason, message)
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, false, reasonNotDegraded, "")
+		setCondition(conditions, generation, loggingv1beta1.ConditionReady, false, reason, message)
+	case degraded:
+		setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, false, reasonRolledOut, "")
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, true, reason, message)
+		setCondition(conditions, generation, loggingv1beta1.ConditionReady, false, reason, message)
+	default:
+		setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, false, reasonRolledOut, "")
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, false, reasonNotDegraded, "")
+		setCondition(conditions, generation, loggingv1beta1.ConditionReady, true, reason, message)
+	}
+}
+
+// setPhaseConditions is a method to translate the phase of a snapshot or security resource into conditions
+func setPhaseConditions(conditions *[]metav1.Condition, generation int64, phase string, reason string, message string) {
+	switch phase {
+	case k8selastic.SnapshotPhaseReady:
+		setRolloutConditions(conditions, generation, false, false, reason, message)
+	case k8selastic.SnapshotPhasePending:
+		setReconcileWaiting(conditions, generation, reason, message)
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, false, reasonNotDegraded, "")
+	default:
+		setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, false, reason, message)
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, true, reason, message)
+		setReconcileFailed(conditions, generation, reason, message)
+	}
+}
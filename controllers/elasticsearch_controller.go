@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +35,9 @@ import (
 	"logging-operator/k8sgo/elasticsearch"
 )
 
+// elasticsearchFinalizer keeps the cluster until its final snapshot is taken and its volumes are cleaned up
+const elasticsearchFinalizer = "logging.opstreelabs.in/elasticsearch-cleanup"
+
 // ElasticsearchReconciler reconciles a Elasticsearch object
 type ElasticsearchReconciler struct {
 	client.Client
@@ -45,7 +49,14 @@ type ElasticsearchReconciler struct {
 //+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=elasticsearches/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=configmaps;events;services;secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets;daemonsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -60,85 +71,134 @@ func (r *ElasticsearchReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{RequeueAfter: time.Second * 10}, err
 	}
 
-	err = secretManager(instance)
-	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	if instance.DeletionTimestamp != nil {
+		return r.finalizeElasticsearch(instance)
+	}
+	if !controllerutil.ContainsFinalizer(instance, elasticsearchFinalizer) {
+		controllerutil.AddFinalizer(instance, elasticsearchFinalizer)
+		if err := r.Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
 	}
 
-	err = k8selastic.CreateElasticSearchService(instance, "master")
+	err = k8selastic.ValidateElasticNodeSets(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "InvalidNodeSets", err)
+	}
+	err = k8selastic.ValidateCertificateRenewBefore(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "InvalidCertificateRenewBefore", err)
+	}
+	err = secretManager(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "SecretsFailed", err)
 	}
 
-	err = k8selastic.SetupElasticSearchMaster(instance)
+	err = k8selastic.CreateSnapshotCredentialsSecret(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "SnapshotCredentialsFailed", err)
 	}
 
-	if instance.Spec.ESData != nil {
-		err = k8selastic.SetupElasticSearchData(instance)
+	if k8selastic.IsIssuerEnabled(instance) {
+		ready, err := k8selastic.IsElasticCertificateReady(instance)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.reconcileFailed(instance, "CertificatesFailed", err)
+		}
+		if !ready {
+			setReconcileWaiting(&instance.Status.Conditions, instance.Generation, "CertificatesPending", "waiting for cert-manager to issue the certificates")
+			return r.updateStatus(instance)
 		}
-		err = k8selastic.CreateElasticSearchService(instance, "data")
+		instance.Status.TLS, err = k8selastic.GetElasticTLSStatus(instance)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.reconcileFailed(instance, "CertificatesFailed", err)
 		}
 	}
 
-	if instance.Spec.ESIngestion != nil {
-		err = k8selastic.SetupElasticSearchIngestion(instance)
-		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
-		}
-		err = k8selastic.CreateElasticSearchService(instance, "ingestion")
-		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
-		}
+	err = r.nodeManager(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "NodeManagementFailed", err)
 	}
 
-	if instance.Spec.ESClient != nil {
-		err = k8selastic.SetupElasticSearchClient(instance)
+	for _, nodeSet := range k8selastic.GetNodeSets(instance) {
+		err = k8selastic.SetupElasticSearchNodeSet(instance, nodeSet)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.reconcileFailed(instance, "NodeSetFailed", err)
 		}
-		err = k8selastic.CreateElasticSearchService(instance, "client")
+		err = k8selastic.CreateElasticSearchService(instance, nodeSet.Name)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.reconcileFailed(instance, "ServiceFailed", err)
 		}
 	}
+	err = k8selastic.DeleteRemovedNodeSets(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "NodeSetCleanupFailed", err)
+	}
+	// data node budgets follow the cluster health of the previous reconcile, so they are tightened without waiting for the cluster
+	err = k8selastic.ReconcileElasticPodDisruptionBudgets(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "PodDisruptionBudgetFailed", err)
+	}
+	err = k8selastic.ReconcileElasticSysctlDaemonSet(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "SysctlDaemonSetFailed", err)
+	}
+	err = k8selastic.DeleteScaledDownVolumes(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "VolumeCleanupFailed", err)
+	}
+	err = k8selastic.ReconcileElasticZones(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "ZoneAwarenessFailed", err)
+	}
 
 	if err := controllerutil.SetControllerReference(instance, instance, r.Scheme); err != nil {
 		return ctrl.Result{RequeueAfter: time.Second * 10}, err
 	}
 
-	instance.Status.ESVersion = instance.Spec.ESVersion
-	instance.Status.ESMaster = instance.Spec.ESMaster.Replicas
-	if instance.Spec.ESData != nil {
-		instance.Status.ESData = instance.Spec.ESData.Replicas
+	clusterInfo, err := elasticgo.GetElasticClusterDetails(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "ClusterUnreachable", err)
 	}
-	if instance.Spec.ESIngestion != nil {
-		instance.Status.ESIngestion = instance.Spec.ESIngestion.Replicas
+
+	k8selastic.SetElasticClusterStatus(instance, clusterInfo)
+	instance.Status.Plugins, err = k8selastic.GetElasticPluginStatus(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "PluginsFailed", err)
 	}
-	if instance.Spec.ESClient != nil {
-		instance.Status.ESClient = instance.Spec.ESClient.Replicas
+	err = k8selastic.ReconcileElasticRestore(instance, clusterInfo)
+	if err != nil {
+		return r.reconcileFailed(instance, "RestoreFailed", err)
 	}
-
-	clusterInfo, err := elasticgo.GetElasticClusterDetails(instance)
+	instance.Status.DataTierWarnings = k8selastic.ValidateDataTiers(instance)
+	instance.Status.DataTiers, err = k8selastic.GetDataTierStatus(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "DataTiersFailed", err)
+	}
+	err = k8selastic.ReconcileElasticClusterSettings(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "ClusterSettingsFailed", err)
+	}
+	err = k8selastic.ReconcileElasticKeystore(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "KeystoreReloadFailed", err)
 	}
-
-	instance.Status.ClusterState = clusterInfo.ClusterState
-	instance.Status.ActiveShards = &clusterInfo.Shards
-	instance.Status.Indices = &clusterInfo.Shards
 
 	if clusterInfo.ClusterState == "green" {
 		err = serviceAccountSecretManager(instance)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+			return r.reconcileFailed(instance, "ServiceAccountFailed", err)
 		}
 	}
+	err = setElasticConditions(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "RolloutUnknown", err)
+	}
+	return r.updateStatus(instance)
+}
+
+// updateStatus is a method to store the status with the generation it was reconciled for
+func (r *ElasticsearchReconciler) updateStatus(instance *loggingv1beta1.Elasticsearch) (ctrl.Result, error) {
+	instance.Status.ObservedGeneration = instance.Generation
 	if err := r.Status().Update(context.TODO(), instance); err != nil {
 		if errors.IsConflict(err) {
 			return ctrl.Result{Requeue: true}, nil
@@ -148,6 +208,80 @@ func (r *ElasticsearchReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
 }
 
+// reconcileFailed is a method to report the failed reconcile step in the status and retry it
+func (r *ElasticsearchReconciler) reconcileFailed(instance *loggingv1beta1.Elasticsearch, reason string, err error) (ctrl.Result, error) {
+	setReconcileFailed(&instance.Status.Conditions, instance.Generation, reason, err.Error())
+	instance.Status.ObservedGeneration = instance.Generation
+	if updateErr := r.Status().Update(context.TODO(), instance); updateErr != nil && !errors.IsConflict(updateErr) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, updateErr
+	}
+	return ctrl.Result{RequeueAfter: time.Second * 10}, err
+}
+
+// setElasticConditions is a method to derive the conditions from the rollout and the health of the cluster
+func setElasticConditions(instance *loggingv1beta1.Elasticsearch) error {
+	conditions := &instance.Status.Conditions
+	generation := instance.Generation
+	reason, message, err := k8selastic.GetElasticRollout(instance)
+	if err != nil {
+		return err
+	}
+	setCondition(conditions, generation, loggingv1beta1.ConditionReconciled, true, reasonReconcileSucceeded, "")
+	if reason != "" {
+		setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, true, reason, message)
+	} else {
+		setCondition(conditions, generation, loggingv1beta1.ConditionProgressing, false, reasonRolledOut, "")
+	}
+
+	clusterState := instance.Status.ClusterState
+	if degradedReason, degradedMessage := k8selastic.GetElasticDegradation(instance); degradedReason != "" {
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, true, degradedReason, degradedMessage)
+	} else {
+		setCondition(conditions, generation, loggingv1beta1.ConditionDegraded, false, reasonNotDegraded, "")
+	}
+
+	// a yellow cluster serves all of its data, single node clusters never get their replicas assigned
+	switch {
+	case reason != "":
+		setCondition(conditions, generation, loggingv1beta1.ConditionReady, false, reason, message)
+	case clusterState != "green" && clusterState != "yellow":
+		setCondition(conditions, generation, loggingv1beta1.ConditionReady, false, "ClusterHealth", fmt.Sprintf("cluster health is %s", clusterState))
+	default:
+		setCondition(conditions, generation, loggingv1beta1.ConditionReady, true, "ClusterAvailable", fmt.Sprintf("cluster health is %s", clusterState))
+	}
+	return nil
+}
+
+// finalizeElasticsearch is a method to take the final snapshot and clean up volumes and secrets of a deleted cluster
+func (r *ElasticsearchReconciler) finalizeElasticsearch(instance *loggingv1beta1.Elasticsearch) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, elasticsearchFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	previousStatus := instance.Status.DeepCopy()
+	done, err := k8selastic.TakeFinalSnapshot(instance)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+	if !done {
+		setCondition(&instance.Status.Conditions, instance.Generation, loggingv1beta1.ConditionProgressing, true, "Deleting", "waiting for the final snapshot")
+		setCondition(&instance.Status.Conditions, instance.Generation, loggingv1beta1.ConditionReady, false, "Deleting", "waiting for the final snapshot")
+	}
+	if !reflect.DeepEqual(previousStatus, &instance.Status) {
+		if err := r.Status().Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	err = k8selastic.CleanupElasticResources(instance)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+	controllerutil.RemoveFinalizer(instance, elasticsearchFinalizer)
+	return ctrl.Result{}, r.Update(context.TODO(), instance)
+}
+
 // secretManager is a method to create and manage secrets
 func secretManager(instance *loggingv1beta1.Elasticsearch) error {
 	if instance.Spec.Security != nil {
@@ -165,21 +299,57 @@ func secretManager(instance *loggingv1beta1.Elasticsearch) error {
 	}
 
 	if instance.Spec.Security != nil {
-		if instance.Spec.Security.TLSEnabled != nil && *instance.Spec.Security.TLSEnabled {
+		if k8selastic.IsIssuerEnabled(instance) {
+			return k8selastic.CreateElasticCertificates(instance)
+		}
+		if k8selastic.IsGeneratedCertificateRequired(instance) {
 			tlsSecretName := fmt.Sprintf("%s-%s", instance.ObjectMeta.Name, "tls-cert")
-			_, err := k8sgo.GetSecret(tlsSecretName, instance.Namespace)
+			caSecretName := fmt.Sprintf("%s-%s", instance.ObjectMeta.Name, "ca-cert")
+			tlsSecret, err := k8sgo.GetSecret(tlsSecretName, instance.Namespace)
+			_, caErr := k8sgo.GetSecret(caSecretName, instance.Namespace)
 
-			if err != nil {
+			// secrets created by older releases only contain the shared p12 bundle
+			if err != nil || caErr != nil || tlsSecret.Data["tls.crt"] == nil {
 				err = k8selastic.CreateElasticTLSSecret(instance)
 				if err != nil {
 					return err
 				}
 			}
+			err = k8selastic.ReconcileElasticTLSCertificates(instance)
+			if err != nil {
+				return err
+			}
+			instance.Status.TLS, err = k8selastic.GetElasticTLSStatus(instance)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// nodeManager is a method to orchestrate version upgrades, scale downs and volume expansion and persist their progress
+func (r *ElasticsearchReconciler) nodeManager(instance *loggingv1beta1.Elasticsearch) error {
+	previousStatus := instance.Status.DeepCopy()
+	err := k8selastic.ReconcileElasticScaleDown(instance)
+	if err != nil {
+		return err
+	}
+	err = k8selastic.ReconcileElasticUpgrade(instance)
+	if err != nil {
+		return err
+	}
+	err = k8selastic.ReconcileElasticVolumeExpansion(instance)
+	if err != nil {
+		return err
+	}
+	// statefulsets are rendered from the upgrade status, so it has to be stored before they are updated
+	if !reflect.DeepEqual(previousStatus, &instance.Status) {
+		return r.Status().Update(context.TODO(), instance)
+	}
+	return nil
+}
+
 // serviceAccountSecretManager is a method for service account
 func serviceAccountSecretManager(instance *loggingv1beta1.Elasticsearch) error {
 	if instance.Spec.Security != nil {
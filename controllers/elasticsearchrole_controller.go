@@ -0,0 +1,120 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo/elasticsearch"
+)
+
+// securityFinalizer removes roles, users and api keys from elasticsearch before their objects are deleted
+const securityFinalizer = "logging.opstreelabs.in/security-cleanup"
+
+// ElasticsearchRoleReconciler reconciles a ElasticsearchRole object
+type ElasticsearchRoleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=elasticsearchroles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=elasticsearchroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=elasticsearchroles/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ElasticsearchRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance := &loggingv1beta1.ElasticsearchRole{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+
+	elasticInstance := &loggingv1beta1.Elasticsearch{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.ElasticsearchRef.Name, Namespace: instance.Namespace}, elasticInstance)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+	elasticFound := err == nil && elasticInstance.DeletionTimestamp == nil
+
+	if instance.DeletionTimestamp != nil {
+		if controllerutil.ContainsFinalizer(instance, securityFinalizer) {
+			if elasticFound && elasticInstance.Spec.Security != nil {
+				err = elasticgo.DeleteRole(elasticInstance, instance.ObjectMeta.Name)
+				if err != nil {
+					return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(instance, securityFinalizer)
+			return ctrl.Result{}, r.Update(context.TODO(), instance)
+		}
+		return ctrl.Result{}, nil
+	}
+	if !controllerutil.ContainsFinalizer(instance, securityFinalizer) {
+		controllerutil.AddFinalizer(instance, securityFinalizer)
+		if err := r.Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+
+	previousStatus := instance.Status.DeepCopy()
+	reason := "RoleApplied"
+	if !elasticFound {
+		reason = "ElasticsearchNotFound"
+		instance.Status.Phase = k8selastic.SecurityPhasePending
+		instance.Status.Message = "elasticsearch cluster not found"
+	} else {
+		err = k8selastic.ReconcileElasticsearchRole(elasticInstance, instance)
+		if err != nil {
+			reason = "RoleFailed"
+			instance.Status.Phase = k8selastic.SecurityPhaseFailed
+			instance.Status.Message = err.Error()
+		} else {
+			instance.Status.Phase = k8selastic.SecurityPhaseReady
+			instance.Status.Message = ""
+		}
+	}
+	setPhaseConditions(&instance.Status.Conditions, instance.Generation, instance.Status.Phase, reason, instance.Status.Message)
+	instance.Status.ObservedGeneration = instance.Generation
+	if !reflect.DeepEqual(previousStatus, &instance.Status) {
+		if err := r.Status().Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ElasticsearchRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&loggingv1beta1.ElasticsearchRole{}).
+		Complete(r)
+}
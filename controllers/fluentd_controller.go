@@ -18,8 +18,10 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -57,21 +59,23 @@ func (r *FluentdReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 	err = setupFluentdRBAC(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "RBACFailed", err)
 	}
 	err = k8sfluentd.CreateFluentdConfigMap(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "ConfigMapFailed", err)
 	}
 	err = k8sfluentd.CreateFluentdDaemonSet(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "DaemonSetFailed", err)
 	}
-	daemonSetCount, err := k8sgo.GetDaemonSetCount(instance.Namespace, instance.ObjectMeta.Name)
+	daemonSet, err := k8sgo.GetDaemonSet(instance.Namespace, instance.ObjectMeta.Name)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "DaemonSetFailed", err)
 	}
-	instance.Status.TotalAgents = daemonSetCount
+	instance.Status.TotalAgents = &daemonSet.Status.CurrentNumberScheduled
+	setFluentdConditions(instance, daemonSet)
+	instance.Status.ObservedGeneration = instance.Generation
 	if err := r.Status().Update(context.TODO(), instance); err != nil {
 		if errors.IsConflict(err) {
 			return ctrl.Result{Requeue: true}, nil
@@ -81,6 +85,30 @@ func (r *FluentdReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
 }
 
+// reconcileFailed is a method to report the failed reconcile step in the status and retry it
+func (r *FluentdReconciler) reconcileFailed(instance *loggingv1beta1.Fluentd, reason string, err error) (ctrl.Result, error) {
+	setReconcileFailed(&instance.Status.Conditions, instance.Generation, reason, err.Error())
+	instance.Status.ObservedGeneration = instance.Generation
+	if updateErr := r.Status().Update(context.TODO(), instance); updateErr != nil && !errors.IsConflict(updateErr) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, updateErr
+	}
+	return ctrl.Result{RequeueAfter: time.Second * 10}, err
+}
+
+// setFluentdConditions is a method to derive the conditions from the rollout of the fluentd daemonset
+func setFluentdConditions(instance *loggingv1beta1.Fluentd, daemonSet *appsv1.DaemonSet) {
+	status := daemonSet.Status
+	message := fmt.Sprintf("%d of %d agents are available", status.NumberAvailable, status.DesiredNumberScheduled)
+	switch {
+	case status.ObservedGeneration < daemonSet.Generation || status.UpdatedNumberScheduled < status.DesiredNumberScheduled:
+		setRolloutConditions(&instance.Status.Conditions, instance.Generation, true, false, reasonRollingOut, message)
+	case status.NumberAvailable < status.DesiredNumberScheduled:
+		setRolloutConditions(&instance.Status.Conditions, instance.Generation, false, true, "AgentsUnavailable", message)
+	default:
+		setRolloutConditions(&instance.Status.Conditions, instance.Generation, false, false, "AgentsAvailable", message)
+	}
+}
+
 // setupFluentdRBAC is a method to setup RBAC access for Fluentd
 func setupFluentdRBAC(instance *loggingv1beta1.Fluentd) error {
 	_, err := k8sgo.GetServiceAccount(instance.ObjectMeta.Name, instance.Namespace)
@@ -18,11 +18,13 @@ package controllers
 
 import (
 	"context"
+	"reflect"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	loggingv1beta1 "logging-operator/api/v1beta1"
 )
@@ -39,18 +41,26 @@ type IndexLifeCycleReconciler struct {
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the IndexLifeCycle object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.0/pkg/reconcile
 func (r *IndexLifeCycleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
-
-	// TODO(user): your logic here
+	instance := &loggingv1beta1.IndexLifeCycle{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
 
+	// the spec is not applied to elasticsearch yet, so the resource never becomes ready
+	previousStatus := instance.Status.DeepCopy()
+	setRolloutConditions(&instance.Status.Conditions, instance.Generation, false, false, "NotImplemented", "")
+	setCondition(&instance.Status.Conditions, instance.Generation, loggingv1beta1.ConditionReady, false, "NotImplemented", "index lifecycle policies are not managed by the operator yet")
+	instance.Status.ObservedGeneration = instance.Generation
+	if !reflect.DeepEqual(previousStatus, &instance.Status) {
+		if err := r.Status().Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -18,14 +18,18 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
 	"logging-operator/k8sgo/kibana"
 )
 
@@ -39,6 +43,7 @@ type KibanaReconciler struct {
 //+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=kibanas/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=kibanas/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -54,15 +59,65 @@ func (r *KibanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 	err = k8skibana.CreateKibanaSetup(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "DeploymentFailed", err)
 	}
 	err = k8skibana.CreateKibanaService(instance)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		return r.reconcileFailed(instance, "ServiceFailed", err)
+	}
+	err = k8skibana.CreateKibanaPodDisruptionBudget(instance)
+	if err != nil {
+		return r.reconcileFailed(instance, "PodDisruptionBudgetFailed", err)
+	}
+	deployment, err := k8sgo.GetDeployment(instance.Namespace, instance.ObjectMeta.Name)
+	if err != nil {
+		return r.reconcileFailed(instance, "DeploymentFailed", err)
+	}
+	setKibanaConditions(instance, deployment)
+	instance.Status.ObservedGeneration = instance.Generation
+	if err := r.Status().Update(context.TODO(), instance); err != nil {
+		if errors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
 	}
 	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
 }
 
+// reconcileFailed is a method to report the failed reconcile step in the status and retry it
+func (r *KibanaReconciler) reconcileFailed(instance *loggingv1beta1.Kibana, reason string, err error) (ctrl.Result, error) {
+	setReconcileFailed(&instance.Status.Conditions, instance.Generation, reason, err.Error())
+	instance.Status.ObservedGeneration = instance.Generation
+	if updateErr := r.Status().Update(context.TODO(), instance); updateErr != nil && !errors.IsConflict(updateErr) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, updateErr
+	}
+	return ctrl.Result{RequeueAfter: time.Second * 10}, err
+}
+
+// setKibanaConditions is a method to derive the conditions from the rollout of the kibana deployment
+func setKibanaConditions(instance *loggingv1beta1.Kibana, deployment *appsv1.Deployment) {
+	var replicas int32 = 1
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	message := fmt.Sprintf("%d of %d kibana pods are available", status.AvailableReplicas, replicas)
+	for _, condition := range status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
+			setRolloutConditions(&instance.Status.Conditions, instance.Generation, false, true, condition.Reason, condition.Message)
+			return
+		}
+	}
+	switch {
+	case status.ObservedGeneration < deployment.Generation || status.UpdatedReplicas < replicas || status.Replicas > replicas:
+		setRolloutConditions(&instance.Status.Conditions, instance.Generation, true, false, reasonRollingOut, message)
+	case status.AvailableReplicas < replicas:
+		setRolloutConditions(&instance.Status.Conditions, instance.Generation, false, true, "PodsUnavailable", message)
+	default:
+		setRolloutConditions(&instance.Status.Conditions, instance.Generation, false, false, "KibanaAvailable", message)
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KibanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
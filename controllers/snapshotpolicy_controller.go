@@ -0,0 +1,133 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo/elasticsearch"
+)
+
+// SnapshotPolicyReconciler reconciles a SnapshotPolicy object
+type SnapshotPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=snapshotpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=snapshotpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=snapshotpolicies/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *SnapshotPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance := &loggingv1beta1.SnapshotPolicy{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+
+	elasticInstance := &loggingv1beta1.Elasticsearch{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.ElasticsearchRef.Name, Namespace: instance.Namespace}, elasticInstance)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+	elasticFound := err == nil
+
+	if instance.DeletionTimestamp != nil {
+		if controllerutil.ContainsFinalizer(instance, snapshotFinalizer) {
+			if elasticFound {
+				err = elasticgo.DeleteSnapshotPolicy(elasticInstance, instance.ObjectMeta.Name)
+				if err != nil {
+					return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(instance, snapshotFinalizer)
+			return ctrl.Result{}, r.Update(context.TODO(), instance)
+		}
+		return ctrl.Result{}, nil
+	}
+	if !controllerutil.ContainsFinalizer(instance, snapshotFinalizer) {
+		controllerutil.AddFinalizer(instance, snapshotFinalizer)
+		if err := r.Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+
+	previousStatus := instance.Status.DeepCopy()
+	reason := "PolicyApplied"
+	repository := &loggingv1beta1.SnapshotRepository{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.Repository, Namespace: instance.Namespace}, repository)
+	switch {
+	case err != nil && !errors.IsNotFound(err):
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	case !elasticFound:
+		reason = "ElasticsearchNotFound"
+		instance.Status.Phase = k8selastic.SnapshotPhasePending
+		instance.Status.Message = "elasticsearch cluster not found"
+	case err != nil || repository.Status.Phase != k8selastic.SnapshotPhaseReady:
+		// policies can only be created for repositories which are registered in elasticsearch
+		reason = "RepositoryNotReady"
+		instance.Status.Phase = k8selastic.SnapshotPhasePending
+		instance.Status.Message = fmt.Sprintf("snapshot repository %s is not ready", instance.Spec.Repository)
+	default:
+		err = k8selastic.ReconcileSnapshotPolicy(elasticInstance, instance)
+		if err != nil {
+			reason = "PolicyFailed"
+			instance.Status.Phase = k8selastic.SnapshotPhaseFailed
+			instance.Status.Message = err.Error()
+		} else {
+			instance.Status.Phase = k8selastic.SnapshotPhaseReady
+			instance.Status.Message = ""
+		}
+	}
+	setPhaseConditions(&instance.Status.Conditions, instance.Generation, instance.Status.Phase, reason, instance.Status.Message)
+	// the policy keeps running after a failed snapshot, which is reported until the next one succeeds
+	lastFailure, lastSuccess := instance.Status.LastFailure, instance.Status.LastSuccess
+	if instance.Status.Phase == k8selastic.SnapshotPhaseReady && lastFailure != nil && (lastSuccess == nil || lastSuccess.Before(lastFailure)) {
+		setCondition(&instance.Status.Conditions, instance.Generation, loggingv1beta1.ConditionDegraded, true, "SnapshotFailed", fmt.Sprintf("snapshot %s failed: %s", instance.Status.LastFailureName, instance.Status.LastFailureDetails))
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+	if !reflect.DeepEqual(previousStatus, &instance.Status) {
+		if err := r.Status().Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&loggingv1beta1.SnapshotPolicy{}).
+		Complete(r)
+}
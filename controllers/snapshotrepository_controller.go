@@ -0,0 +1,124 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo/elasticsearch"
+)
+
+// snapshotFinalizer removes repositories and policies from elasticsearch before their objects are deleted
+const snapshotFinalizer = "logging.opstreelabs.in/snapshot-cleanup"
+
+// SnapshotRepositoryReconciler reconciles a SnapshotRepository object
+type SnapshotRepositoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=snapshotrepositories,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=snapshotrepositories/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=logging.logging.opstreelabs.in,resources=snapshotrepositories/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *SnapshotRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance := &loggingv1beta1.SnapshotRepository{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+
+	elasticInstance := &loggingv1beta1.Elasticsearch{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.ElasticsearchRef.Name, Namespace: instance.Namespace}, elasticInstance)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, err
+	}
+	elasticFound := err == nil
+
+	if instance.DeletionTimestamp != nil {
+		if controllerutil.ContainsFinalizer(instance, snapshotFinalizer) {
+			// the repository is still needed for the final snapshot of a cluster which is deleted at the same time
+			if elasticFound && k8selastic.IsFinalSnapshotPending(elasticInstance, instance.ObjectMeta.Name) {
+				return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+			}
+			if elasticFound {
+				err = elasticgo.DeleteSnapshotRepository(elasticInstance, instance.ObjectMeta.Name)
+				if err != nil {
+					return ctrl.Result{RequeueAfter: time.Second * 10}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(instance, snapshotFinalizer)
+			return ctrl.Result{}, r.Update(context.TODO(), instance)
+		}
+		return ctrl.Result{}, nil
+	}
+	if !controllerutil.ContainsFinalizer(instance, snapshotFinalizer) {
+		controllerutil.AddFinalizer(instance, snapshotFinalizer)
+		if err := r.Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+
+	previousStatus := instance.Status.DeepCopy()
+	reason := "RepositoryVerified"
+	if !elasticFound {
+		reason = "ElasticsearchNotFound"
+		instance.Status.Phase = k8selastic.SnapshotPhasePending
+		instance.Status.Message = "elasticsearch cluster not found"
+	} else {
+		err = k8selastic.ReconcileSnapshotRepository(elasticInstance, instance)
+		if err != nil {
+			reason = "RepositoryFailed"
+			instance.Status.Phase = k8selastic.SnapshotPhaseFailed
+			instance.Status.Message = err.Error()
+		} else {
+			instance.Status.Phase = k8selastic.SnapshotPhaseReady
+			instance.Status.Message = ""
+		}
+	}
+	setPhaseConditions(&instance.Status.Conditions, instance.Generation, instance.Status.Phase, reason, instance.Status.Message)
+	instance.Status.ObservedGeneration = instance.Generation
+	if !reflect.DeepEqual(previousStatus, &instance.Status) {
+		if err := r.Status().Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&loggingv1beta1.SnapshotRepository{}).
+		Complete(r)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticgo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// ESAPIKeyRequest is a struct for the creation of an API key
+type ESAPIKeyRequest struct {
+	Name            string            `json:"name"`
+	RoleDescriptors map[string]ESRole `json:"role_descriptors,omitempty"`
+	Expiration      string            `json:"expiration,omitempty"`
+}
+
+// ESAPIKey is a struct for a created API key, the key itself is only returned on creation
+type ESAPIKey struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Expiration int64  `json:"expiration,omitempty"`
+	APIKey     string `json:"api_key"`
+	Encoded    string `json:"encoded,omitempty"`
+}
+
+// ESAPIKeyInfo is a struct for the information of an API key
+type ESAPIKeyInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Creation    int64  `json:"creation"`
+	Expiration  int64  `json:"expiration,omitempty"`
+	Invalidated bool   `json:"invalidated"`
+}
+
+// CreateAPIKey is a method to create an API key which is owned by the operator user
+func CreateAPIKey(cr *loggingv1beta1.Elasticsearch, keyRequest ESAPIKeyRequest) (*ESAPIKey, error) {
+	var apiKey ESAPIKey
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	body, err := json.Marshal(keyRequest)
+	if err != nil {
+		return nil, err
+	}
+	req := esapi.SecurityCreateAPIKeyRequest{Body: strings.NewReader(string(body))}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("creation of api key failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&apiKey)
+	if err != nil {
+		return nil, err
+	}
+	// the encoded key is only returned by elasticsearch 7.16 and later
+	if apiKey.Encoded == "" {
+		apiKey.Encoded = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", apiKey.ID, apiKey.APIKey)))
+	}
+	return &apiKey, nil
+}
+
+// GetAPIKey is a method to get the information of an API key, nil is returned if it does not exist
+func GetAPIKey(cr *loggingv1beta1.Elasticsearch, id string) (*ESAPIKeyInfo, error) {
+	var apiKeys struct {
+		APIKeys []ESAPIKeyInfo `json:"api_keys"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	req := esapi.SecurityGetAPIKeyRequest{ID: id}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("fetching api key failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&apiKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, apiKey := range apiKeys.APIKeys {
+		if apiKey.ID == id {
+			return &apiKey, nil
+		}
+	}
+	return nil, nil
+}
+
+// InvalidateAPIKey is a method to invalidate an API key, requests with the key are rejected afterwards
+func InvalidateAPIKey(cr *loggingv1beta1.Elasticsearch, id string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	body, err := json.Marshal(map[string][]string{"ids": {id}})
+	if err != nil {
+		return err
+	}
+	req := esapi.SecurityInvalidateAPIKeyRequest{Body: strings.NewReader(string(body))}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("invalidation of api key failed: %s", res.String())
+	}
+	return nil
+}
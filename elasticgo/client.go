@@ -31,8 +31,18 @@ import (
 
 // ESClusterDetails is a method for return ESClusterDetails
 type ESClusterDetails struct {
-	ClusterState string `json:"status"`
-	Shards       int32  `json:"active_shards"`
+	ClusterState       string `json:"status"`
+	Shards             int32  `json:"active_shards"`
+	PrimaryShards      int32  `json:"active_primary_shards"`
+	RelocatingShards   int32  `json:"relocating_shards"`
+	InitializingShards int32  `json:"initializing_shards"`
+	UnassignedShards   int32  `json:"unassigned_shards"`
+	NumberOfNodes      int32  `json:"number_of_nodes"`
+	NumberOfDataNodes  int32  `json:"number_of_data_nodes"`
+	IndexHealth        map[string]struct {
+		Status string `json:"status"`
+	} `json:"indices"`
+	Nodes []ESNodeDetails `json:"-"`
 }
 
 // ElasticsearchToken is a interface for elasticsearch token
@@ -46,14 +56,32 @@ type ElasticsearchToken struct {
 
 // generateElasticClient is a method to generate client for elasticsearch
 func generateElasticClient(cr *loggingv1beta1.Elasticsearch) (esapi.Transport, error) {
+	var elasticPassword string
+	if cr.Spec.Security == nil {
+		return generateElasticClientWithCredentials(cr, "", "")
+	}
+	if cr.Spec.Security.ExistingSecret != nil {
+		elasticPassword = k8sgo.GetElasticDBPassword(*cr.Spec.Security.ExistingSecret, cr.Namespace)
+	} else {
+		elasticPassword = k8sgo.GetElasticDBPassword(fmt.Sprintf("%s-password", cr.ObjectMeta.Name), cr.Namespace)
+	}
+	return generateElasticClientWithCredentials(cr, "elastic", elasticPassword)
+}
+
+// generateElasticClientWithCredentials is a method to generate client for elasticsearch which authenticates as the given user
+func generateElasticClientWithCredentials(cr *loggingv1beta1.Elasticsearch, username string, password string) (esapi.Transport, error) {
 	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
-	var urlScheme, elasticPassword string
-	if cr.Spec.Security != nil {
+	var urlScheme string
+	if cr.Spec.Security.IsHTTPTLSEnabled() {
 		urlScheme = "https"
 	} else {
 		urlScheme = "http"
 	}
-	elasticURL := fmt.Sprintf("%s://%s-master:9200", urlScheme, cr.ObjectMeta.Name)
+	serviceName, err := getClientServiceName(cr)
+	if err != nil {
+		return nil, err
+	}
+	elasticURL := fmt.Sprintf("%s://%s:9200", urlScheme, serviceName)
 	cfg := elasticsearch.Config{
 		Addresses: []string{
 			elasticURL,
@@ -63,16 +91,8 @@ func generateElasticClient(cr *loggingv1beta1.Elasticsearch) (esapi.Transport, e
 				InsecureSkipVerify: true,
 			},
 		},
-	}
-	if cr.Spec.Security != nil {
-		if cr.Spec.Security.ExistingSecret != nil {
-			elasticPassword = k8sgo.GetElasticDBPassword(*cr.Spec.Security.ExistingSecret, cr.Namespace)
-		} else {
-			elasticPassword = k8sgo.GetElasticDBPassword(fmt.Sprintf("%s-password", cr.ObjectMeta.Name), cr.Namespace)
-		}
-
-		cfg.Username = "elastic"
-		cfg.Password = elasticPassword
+		Username: username,
+		Password: password,
 	}
 	es, err := elasticsearch.NewClient(cfg)
 	if err != nil {
@@ -82,7 +102,22 @@ func generateElasticClient(cr *loggingv1beta1.Elasticsearch) (esapi.Transport, e
 	return es, nil
 }
 
-// GetElasticClusterDetails is a method to get health of elastic
+// getClientServiceName is a method to get the service of the first master eligible node set, which every cluster has
+func getClientServiceName(cr *loggingv1beta1.Elasticsearch) (string, error) {
+	if cr.Spec.ESMaster != nil {
+		return fmt.Sprintf("%s-master", cr.ObjectMeta.Name), nil
+	}
+	for _, nodeSet := range cr.Spec.NodeSets {
+		for _, role := range nodeSet.Roles {
+			if role == "master" {
+				return fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, nodeSet.Name), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("cluster %s has no master eligible node set", cr.ObjectMeta.Name)
+}
+
+// GetElasticClusterDetails is a method to get the health, the nodes and the shard allocation of elastic
 func GetElasticClusterDetails(cr *loggingv1beta1.Elasticsearch) (ESClusterDetails, error) {
 	var clusterInfo ESClusterDetails
 	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
@@ -91,18 +126,26 @@ func GetElasticClusterDetails(cr *loggingv1beta1.Elasticsearch) (ESClusterDetail
 		logger.Error(err, "Failed in generating elasticsearch client")
 		return clusterInfo, err
 	}
-	req := esapi.ClusterHealthRequest{}
+	// the index level is only needed to count the indices, their shard details are filtered out
+	req := esapi.ClusterHealthRequest{Level: "indices", FilterPath: []string{"status", "*_shards", "number_of_*", "indices.*.status"}}
 	res, err := req.Do(context.Background(), esClient)
 	if err != nil {
 		logger.Error(err, "Error while making request to elasticsearch")
 		return clusterInfo, err
 	}
 	defer res.Body.Close()
+	if res.IsError() {
+		return clusterInfo, fmt.Errorf("fetching cluster health failed: %s", res.String())
+	}
 	decoder := json.NewDecoder(res.Body)
 	err = decoder.Decode(&clusterInfo)
 	if err != nil {
 		return clusterInfo, err
 	}
+	clusterInfo.Nodes, err = getElasticNodeDetails(cr, esClient)
+	if err != nil {
+		return clusterInfo, err
+	}
 	return clusterInfo, nil
 }
 
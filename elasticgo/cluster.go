@@ -0,0 +1,222 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// SetShardAllocation is a method to change the shard allocation of elasticsearch, an empty value resets it
+func SetShardAllocation(cr *loggingv1beta1.Elasticsearch, allocation string) error {
+	var allocationValue interface{}
+	if allocation != "" {
+		allocationValue = allocation
+	}
+	return putClusterSettings(cr, map[string]interface{}{
+		"persistent": map[string]interface{}{
+			"cluster.routing.allocation.enable": allocationValue,
+		},
+	})
+}
+
+// SetAllocationExclusion is a method to move all shards away from the given nodes, no nodes clears the exclusion
+func SetAllocationExclusion(cr *loggingv1beta1.Elasticsearch, nodes []string) error {
+	var exclusionValue interface{}
+	if len(nodes) > 0 {
+		exclusionValue = strings.Join(nodes, ",")
+	}
+	return putClusterSettings(cr, map[string]interface{}{
+		"persistent": map[string]interface{}{
+			"cluster.routing.allocation.exclude._name": exclusionValue,
+		},
+	})
+}
+
+// CountNodeShards is a method to count the shards which are still allocated on the given nodes
+func CountNodeShards(cr *loggingv1beta1.Elasticsearch, nodes []string) (int32, error) {
+	var shards []struct {
+		Node string `json:"node"`
+	}
+	var count int32
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return count, err
+	}
+	req := esapi.CatShardsRequest{Format: "json", H: []string{"node"}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return count, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return count, fmt.Errorf("listing of shards failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&shards)
+	if err != nil {
+		return count, err
+	}
+	for _, shard := range shards {
+		// relocating shards are reported as "<source> -> <ip> <id> <target>"
+		fields := strings.Fields(shard.Node)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, node := range nodes {
+			if fields[0] == node {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// AddVotingConfigExclusions is a method to remove master nodes from the voting configuration
+func AddVotingConfigExclusions(cr *loggingv1beta1.Elasticsearch, nodes []string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	// the request only returns successfully once the new voting configuration is committed
+	req := esapi.ClusterPostVotingConfigExclusionsRequest{NodeNames: strings.Join(nodes, ","), Timeout: 30 * time.Second}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("adding voting config exclusions failed: %s", res.String())
+	}
+	return nil
+}
+
+// ClearVotingConfigExclusions is a method to clear the voting configuration exclusions once the nodes left the cluster
+func ClearVotingConfigExclusions(cr *loggingv1beta1.Elasticsearch) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	waitForRemoval := true
+	req := esapi.ClusterDeleteVotingConfigExclusionsRequest{WaitForRemoval: &waitForRemoval}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("clearing voting config exclusions failed: %s", res.String())
+	}
+	return nil
+}
+
+// FlushIndices is a method to flush all indices of elasticsearch
+func FlushIndices(cr *loggingv1beta1.Elasticsearch) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	req := esapi.IndicesFlushRequest{}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("flush of indices failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetPersistentClusterSettings is a method to get the persistent cluster settings of elasticsearch as flat settings
+func GetPersistentClusterSettings(cr *loggingv1beta1.Elasticsearch) (map[string]interface{}, error) {
+	var settings struct {
+		Persistent map[string]interface{} `json:"persistent"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	flatSettings := true
+	req := esapi.ClusterGetSettingsRequest{FlatSettings: &flatSettings}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("fetching cluster settings failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings.Persistent, nil
+}
+
+// PutPersistentClusterSettings is a method to update persistent cluster settings, a nil value resets a setting
+func PutPersistentClusterSettings(cr *loggingv1beta1.Elasticsearch, settings map[string]interface{}) error {
+	return putClusterSettings(cr, map[string]interface{}{
+		"persistent": settings,
+	})
+}
+
+// putClusterSettings is a method to update the cluster settings of elasticsearch
+func putClusterSettings(cr *loggingv1beta1.Elasticsearch, settings map[string]interface{}) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	req := esapi.ClusterPutSettingsRequest{Body: strings.NewReader(string(body))}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("update of cluster settings failed: %s", res.String())
+	}
+	return nil
+}
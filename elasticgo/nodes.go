@@ -0,0 +1,270 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// ESNodeStats is a struct for the roles and disk usage of an elasticsearch node
+type ESNodeStats struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+	FS    struct {
+		Total struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+	} `json:"fs"`
+}
+
+// ESNodeDetails is a struct for the roles, version, heap and disk usage and shards of an elasticsearch node
+type ESNodeDetails struct {
+	Name             string
+	Roles            []string
+	Master           bool
+	Version          string
+	HeapUsedPercent  int32
+	HeapMaxBytes     int64
+	DiskTotalBytes   int64
+	DiskUsedBytes    int64
+	DiskUsedPercent  int32
+	Shards           int32
+	DiskIndicesBytes int64
+}
+
+// nodeRoleNames maps the abbreviated roles of the cat nodes api to the role names
+var nodeRoleNames = map[rune]string{
+	'c': "data_cold",
+	'd': "data",
+	'f': "data_frozen",
+	'h': "data_hot",
+	'i': "ingest",
+	'l': "ml",
+	'm': "master",
+	'r': "remote_cluster_client",
+	's': "data_content",
+	't': "transform",
+	'v': "voting_only",
+	'w': "data_warm",
+}
+
+// GetElasticNodeStats is a method to get the roles and disk usage of all elasticsearch nodes
+func GetElasticNodeStats(cr *loggingv1beta1.Elasticsearch) ([]ESNodeStats, error) {
+	var nodeStats struct {
+		Nodes map[string]ESNodeStats `json:"nodes"`
+	}
+	var nodes []ESNodeStats
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nodes, err
+	}
+	req := esapi.NodesStatsRequest{Metric: []string{"fs"}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nodes, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nodes, fmt.Errorf("fetching node stats failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&nodeStats)
+	if err != nil {
+		return nodes, err
+	}
+	for _, node := range nodeStats.Nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// getElasticNodeDetails is a method to get the nodes from the cat nodes api joined with their shard allocation
+func getElasticNodeDetails(cr *loggingv1beta1.Elasticsearch, esClient esapi.Transport) ([]ESNodeDetails, error) {
+	var catNodes []struct {
+		Name            string `json:"name"`
+		Role            string `json:"node.role"`
+		Master          string `json:"master"`
+		Version         string `json:"version"`
+		HeapPercent     string `json:"heap.percent"`
+		HeapMax         string `json:"heap.max"`
+		DiskTotal       string `json:"disk.total"`
+		DiskUsed        string `json:"disk.used"`
+		DiskUsedPercent string `json:"disk.used_percent"`
+	}
+	var catAllocation []struct {
+		Node        string `json:"node"`
+		Shards      string `json:"shards"`
+		DiskIndices string `json:"disk.indices"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	nodesReq := esapi.CatNodesRequest{Format: "json", Bytes: "b", H: []string{"name", "node.role", "master", "version", "heap.percent", "heap.max", "disk.total", "disk.used", "disk.used_percent"}}
+	res, err := nodesReq.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("listing of nodes failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&catNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	allocationReq := esapi.CatAllocationRequest{Format: "json", Bytes: "b", H: []string{"node", "shards", "disk.indices"}}
+	allocationRes, err := allocationReq.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer allocationRes.Body.Close()
+	if allocationRes.IsError() {
+		return nil, fmt.Errorf("listing of shard allocation failed: %s", allocationRes.String())
+	}
+	err = json.NewDecoder(allocationRes.Body).Decode(&catAllocation)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []ESNodeDetails
+	for _, catNode := range catNodes {
+		node := ESNodeDetails{
+			Name:            catNode.Name,
+			Master:          catNode.Master == "*",
+			Version:         catNode.Version,
+			HeapUsedPercent: int32(parseCatFloat(catNode.HeapPercent)),
+			HeapMaxBytes:    int64(parseCatFloat(catNode.HeapMax)),
+			DiskTotalBytes:  int64(parseCatFloat(catNode.DiskTotal)),
+			DiskUsedBytes:   int64(parseCatFloat(catNode.DiskUsed)),
+			DiskUsedPercent: int32(parseCatFloat(catNode.DiskUsedPercent)),
+		}
+		for _, abbreviation := range catNode.Role {
+			if role, ok := nodeRoleNames[abbreviation]; ok {
+				node.Roles = append(node.Roles, role)
+			}
+		}
+		// unassigned shards are listed in an allocation row of their own
+		for _, allocation := range catAllocation {
+			if allocation.Node == catNode.Name {
+				node.Shards = int32(parseCatFloat(allocation.Shards))
+				node.DiskIndicesBytes = int64(parseCatFloat(allocation.DiskIndices))
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// parseCatFloat is a method to parse the numbers which the cat apis return as strings, missing values are zero
+func parseCatFloat(value string) float64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// ESPlugin is a struct for a plugin which is installed on an elasticsearch node
+type ESPlugin struct {
+	Node      string `json:"name"`
+	Component string `json:"component"`
+	Version   string `json:"version"`
+}
+
+// GetElasticPlugins is a method to list the plugins which are installed on every elasticsearch node
+func GetElasticPlugins(cr *loggingv1beta1.Elasticsearch) ([]ESPlugin, error) {
+	var plugins []ESPlugin
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	req := esapi.CatPluginsRequest{Format: "json"}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("listing of plugins failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&plugins)
+	if err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+// ReloadSecureSettings is a method to reload the reloadable secure settings from the keystore of every elasticsearch node
+func ReloadSecureSettings(cr *loggingv1beta1.Elasticsearch) error {
+	var reloadResult struct {
+		Nodes map[string]struct {
+			Name            string `json:"name"`
+			ReloadException *struct {
+				Reason string `json:"reason"`
+			} `json:"reload_exception"`
+		} `json:"nodes"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	req := esapi.NodesReloadSecureSettingsRequest{}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("reloading of secure settings failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&reloadResult)
+	if err != nil {
+		return err
+	}
+	// the api succeeds as a whole and reports the nodes which could not decrypt or apply the keystore
+	var failures []string
+	for _, node := range reloadResult.Nodes {
+		if node.ReloadException != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", node.Name, node.ReloadException.Reason))
+		}
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("reloading of secure settings failed on %s", strings.Join(failures, ", "))
+	}
+	logger.Info("Secure settings were reloaded")
+	return nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// ESRole is a struct for the privileges of a security role
+type ESRole struct {
+	Cluster      []string                  `json:"cluster"`
+	Indices      []ESIndexPrivileges       `json:"indices"`
+	Applications []ESApplicationPrivileges `json:"applications"`
+	RunAs        []string                  `json:"run_as"`
+}
+
+// ESIndexPrivileges is a struct for the privileges of a role on a group of indices
+type ESIndexPrivileges struct {
+	Names                  []string         `json:"names"`
+	Privileges             []string         `json:"privileges"`
+	FieldSecurity          *ESFieldSecurity `json:"field_security,omitempty"`
+	Query                  string           `json:"query,omitempty"`
+	AllowRestrictedIndices bool             `json:"allow_restricted_indices"`
+}
+
+// ESFieldSecurity is a struct for the field level security of index privileges
+type ESFieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
+}
+
+// ESApplicationPrivileges is a struct for the privileges of a role in an application
+type ESApplicationPrivileges struct {
+	Application string   `json:"application"`
+	Privileges  []string `json:"privileges"`
+	Resources   []string `json:"resources"`
+}
+
+// ESUser is a struct for a user of the native realm, the password is never returned by elasticsearch
+type ESUser struct {
+	Password string   `json:"password,omitempty"`
+	Roles    []string `json:"roles"`
+	FullName string   `json:"full_name,omitempty"`
+	Email    string   `json:"email,omitempty"`
+	Enabled  bool     `json:"enabled"`
+}
+
+// PutRole is a method to create or replace a security role
+func PutRole(cr *loggingv1beta1.Elasticsearch, name string, role ESRole) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	body, err := json.Marshal(role)
+	if err != nil {
+		return err
+	}
+	req := esapi.SecurityPutRoleRequest{Name: name, Body: strings.NewReader(string(body))}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("update of role failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetRole is a method to get a security role, nil is returned if it does not exist
+func GetRole(cr *loggingv1beta1.Elasticsearch, name string) (*ESRole, error) {
+	var roles map[string]ESRole
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	req := esapi.SecurityGetRoleRequest{Name: []string{name}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("fetching role failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&roles)
+	if err != nil {
+		return nil, err
+	}
+	role, ok := roles[name]
+	if !ok {
+		return nil, nil
+	}
+	return &role, nil
+}
+
+// DeleteRole is a method to delete a security role, users keep the name of the role but lose its privileges
+func DeleteRole(cr *loggingv1beta1.Elasticsearch, name string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	req := esapi.SecurityDeleteRoleRequest{Name: name}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deletion of role failed: %s", res.String())
+	}
+	return nil
+}
+
+// PutUser is a method to create or update a user of the native realm, the password is kept when it is empty
+func PutUser(cr *loggingv1beta1.Elasticsearch, username string, user ESUser) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	body, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	req := esapi.SecurityPutUserRequest{Username: username, Body: strings.NewReader(string(body))}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("update of user failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetUser is a method to get a user of the native realm, nil is returned if it does not exist
+func GetUser(cr *loggingv1beta1.Elasticsearch, username string) (*ESUser, error) {
+	var users map[string]ESUser
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	req := esapi.SecurityGetUserRequest{Username: []string{username}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("fetching user failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&users)
+	if err != nil {
+		return nil, err
+	}
+	user, ok := users[username]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// DeleteUser is a method to delete a user of the native realm
+func DeleteUser(cr *loggingv1beta1.Elasticsearch, username string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	req := esapi.SecurityDeleteUserRequest{Username: username}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deletion of user failed: %s", res.String())
+	}
+	return nil
+}
+
+// AuthenticateUser is a method to check if a user can log in with a password
+func AuthenticateUser(cr *loggingv1beta1.Elasticsearch, username string, password string) (bool, error) {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClientWithCredentials(cr, username, password)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return false, err
+	}
+	req := esapi.SecurityAuthenticateRequest{}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized {
+		return false, nil
+	}
+	if res.IsError() {
+		return false, fmt.Errorf("authentication of user failed: %s", res.String())
+	}
+	return true, nil
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// ESSnapshotRepository is a struct for the type and settings of a snapshot repository
+type ESSnapshotRepository struct {
+	Type     string            `json:"type"`
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// ESSnapshotPolicy is a struct for a snapshot lifecycle management policy
+type ESSnapshotPolicy struct {
+	Name       string                     `json:"name"`
+	Schedule   string                     `json:"schedule"`
+	Repository string                     `json:"repository"`
+	Config     *ESSnapshotPolicyConfig    `json:"config,omitempty"`
+	Retention  *ESSnapshotPolicyRetention `json:"retention,omitempty"`
+}
+
+// ESSnapshotPolicyConfig is a struct for the snapshot request of a policy
+type ESSnapshotPolicyConfig struct {
+	Indices            []string `json:"indices,omitempty"`
+	IgnoreUnavailable  *bool    `json:"ignore_unavailable,omitempty"`
+	IncludeGlobalState *bool    `json:"include_global_state,omitempty"`
+}
+
+// ESSnapshotPolicyRetention is a struct for the retention of a policy
+type ESSnapshotPolicyRetention struct {
+	ExpireAfter *string `json:"expire_after,omitempty"`
+	MinCount    *int32  `json:"min_count,omitempty"`
+	MaxCount    *int32  `json:"max_count,omitempty"`
+}
+
+// ESSnapshotPolicyInfo is a struct for a policy and the result of its last executions
+type ESSnapshotPolicyInfo struct {
+	Policy      ESSnapshotPolicy `json:"policy"`
+	LastSuccess *struct {
+		SnapshotName string `json:"snapshot_name"`
+		Time         int64  `json:"time"`
+	} `json:"last_success,omitempty"`
+	LastFailure *struct {
+		SnapshotName string `json:"snapshot_name"`
+		Time         int64  `json:"time"`
+		Details      string `json:"details"`
+	} `json:"last_failure,omitempty"`
+	NextExecutionMillis int64 `json:"next_execution_millis"`
+	Stats               struct {
+		SnapshotsTaken  int64 `json:"snapshots_taken"`
+		SnapshotsFailed int64 `json:"snapshots_failed"`
+	} `json:"stats"`
+}
+
+// PutSnapshotRepository is a method to register and verify a snapshot repository
+func PutSnapshotRepository(cr *loggingv1beta1.Elasticsearch, name string, repository ESSnapshotRepository) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	body, err := json.Marshal(repository)
+	if err != nil {
+		return err
+	}
+	verify := true
+	req := esapi.SnapshotCreateRepositoryRequest{Repository: name, Body: strings.NewReader(string(body)), Verify: &verify}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("registration of snapshot repository failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetSnapshotRepository is a method to get a snapshot repository, nil is returned if it does not exist
+func GetSnapshotRepository(cr *loggingv1beta1.Elasticsearch, name string) (*ESSnapshotRepository, error) {
+	var repositories map[string]ESSnapshotRepository
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	req := esapi.SnapshotGetRepositoryRequest{Repository: []string{name}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("fetching snapshot repository failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&repositories)
+	if err != nil {
+		return nil, err
+	}
+	repository, ok := repositories[name]
+	if !ok {
+		return nil, nil
+	}
+	return &repository, nil
+}
+
+// DeleteSnapshotRepository is a method to unregister a snapshot repository, the stored snapshots are kept
+func DeleteSnapshotRepository(cr *loggingv1beta1.Elasticsearch, name string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	req := esapi.SnapshotDeleteRepositoryRequest{Repository: []string{name}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deletion of snapshot repository failed: %s", res.String())
+	}
+	return nil
+}
+
+// PutSnapshotPolicy is a method to create or update a snapshot lifecycle management policy
+func PutSnapshotPolicy(cr *loggingv1beta1.Elasticsearch, name string, policy ESSnapshotPolicy) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	req := esapi.SlmPutLifecycleRequest{PolicyID: name, Body: strings.NewReader(string(body))}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("update of snapshot policy failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetSnapshotPolicy is a method to get a snapshot lifecycle management policy, nil is returned if it does not exist
+func GetSnapshotPolicy(cr *loggingv1beta1.Elasticsearch, name string) (*ESSnapshotPolicyInfo, error) {
+	var policies map[string]ESSnapshotPolicyInfo
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return nil, err
+	}
+	req := esapi.SlmGetLifecycleRequest{PolicyID: []string{name}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("fetching snapshot policy failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&policies)
+	if err != nil {
+		return nil, err
+	}
+	policy, ok := policies[name]
+	if !ok {
+		return nil, nil
+	}
+	return &policy, nil
+}
+
+// DeleteSnapshotPolicy is a method to delete a snapshot lifecycle management policy
+func DeleteSnapshotPolicy(cr *loggingv1beta1.Elasticsearch, name string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	req := esapi.SlmDeleteLifecycleRequest{PolicyID: name}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deletion of snapshot policy failed: %s", res.String())
+	}
+	return nil
+}
+
+// ESRestoreProgress is a struct for the shard recoveries of a snapshot restore
+type ESRestoreProgress struct {
+	TotalShards     int32
+	RecoveredShards int32
+	TotalBytes      int64
+	RecoveredBytes  int64
+}
+
+// RestoreSnapshot is a method to start the restore of a snapshot without waiting for its completion
+func RestoreSnapshot(cr *loggingv1beta1.Elasticsearch, repository string, snapshot string, indices []string, includeGlobalState *bool) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	restoreBody := map[string]interface{}{}
+	if len(indices) > 0 {
+		restoreBody["indices"] = strings.Join(indices, ",")
+	}
+	if includeGlobalState != nil {
+		restoreBody["include_global_state"] = *includeGlobalState
+	}
+	body, err := json.Marshal(restoreBody)
+	if err != nil {
+		return err
+	}
+	waitForCompletion := false
+	req := esapi.SnapshotRestoreRequest{Repository: repository, Snapshot: snapshot, Body: strings.NewReader(string(body)), WaitForCompletion: &waitForCompletion}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("restore of snapshot failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetRestoreProgress is a method to sum up the shard recoveries which restore a snapshot
+func GetRestoreProgress(cr *loggingv1beta1.Elasticsearch, repository string, snapshot string) (ESRestoreProgress, error) {
+	var progress ESRestoreProgress
+	var recoveries map[string]struct {
+		Shards []struct {
+			Type   string `json:"type"`
+			Stage  string `json:"stage"`
+			Source struct {
+				Repository string `json:"repository"`
+				Snapshot   string `json:"snapshot"`
+			} `json:"source"`
+			Index struct {
+				Size struct {
+					TotalInBytes     int64 `json:"total_in_bytes"`
+					RecoveredInBytes int64 `json:"recovered_in_bytes"`
+				} `json:"size"`
+			} `json:"index"`
+		} `json:"shards"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return progress, err
+	}
+	activeOnly := false
+	req := esapi.IndicesRecoveryRequest{ActiveOnly: &activeOnly}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return progress, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return progress, fmt.Errorf("fetching recoveries failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&recoveries)
+	if err != nil {
+		return progress, err
+	}
+	for _, recovery := range recoveries {
+		for _, shard := range recovery.Shards {
+			if shard.Type != "SNAPSHOT" || shard.Source.Repository != repository || shard.Source.Snapshot != snapshot {
+				continue
+			}
+			progress.TotalShards++
+			if shard.Stage == "DONE" {
+				progress.RecoveredShards++
+			}
+			progress.TotalBytes += shard.Index.Size.TotalInBytes
+			progress.RecoveredBytes += shard.Index.Size.RecoveredInBytes
+		}
+	}
+	return progress, nil
+}
+
+// IsRestoreInProgress is a method to check if the cluster state still holds the restore of a snapshot
+func IsRestoreInProgress(cr *loggingv1beta1.Elasticsearch, repository string, snapshot string) (bool, error) {
+	var clusterState struct {
+		Restore struct {
+			Snapshots []struct {
+				Snapshot   string `json:"snapshot"`
+				Repository string `json:"repository"`
+			} `json:"snapshots"`
+		} `json:"restore"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return false, err
+	}
+	req := esapi.ClusterStateRequest{Metric: []string{"restore"}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return false, fmt.Errorf("fetching restores of cluster state failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&clusterState)
+	if err != nil {
+		return false, err
+	}
+	for _, restore := range clusterState.Restore.Snapshots {
+		if restore.Repository == repository && restore.Snapshot == snapshot {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateSnapshot is a method to start a snapshot without waiting for its completion
+func CreateSnapshot(cr *loggingv1beta1.Elasticsearch, repository string, snapshot string, indices []string) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return err
+	}
+	snapshotBody := map[string]interface{}{}
+	if len(indices) > 0 {
+		snapshotBody["indices"] = strings.Join(indices, ",")
+	}
+	body, err := json.Marshal(snapshotBody)
+	if err != nil {
+		return err
+	}
+	waitForCompletion := false
+	req := esapi.SnapshotCreateRequest{Repository: repository, Snapshot: snapshot, Body: strings.NewReader(string(body)), WaitForCompletion: &waitForCompletion}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("creation of snapshot failed: %s", res.String())
+	}
+	return nil
+}
+
+// GetSnapshotState is a method to get the state of a snapshot, an empty state is returned if it does not exist
+func GetSnapshotState(cr *loggingv1beta1.Elasticsearch, repository string, snapshot string) (string, error) {
+	var snapshots struct {
+		Snapshots []struct {
+			Snapshot string `json:"snapshot"`
+			State    string `json:"state"`
+		} `json:"snapshots"`
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	esClient, err := generateElasticClient(cr)
+	if err != nil {
+		logger.Error(err, "Failed in generating elasticsearch client")
+		return "", err
+	}
+	req := esapi.SnapshotGetRequest{Repository: repository, Snapshot: []string{snapshot}}
+	res, err := req.Do(context.Background(), esClient)
+	if err != nil {
+		logger.Error(err, "Error while making request to elasticsearch")
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if res.IsError() {
+		return "", fmt.Errorf("fetching snapshot failed: %s", res.String())
+	}
+	err = json.NewDecoder(res.Body).Decode(&snapshots)
+	if err != nil {
+		return "", err
+	}
+	for _, info := range snapshots.Snapshots {
+		if info.Snapshot == snapshot {
+			return info.State, nil
+		}
+	}
+	return "", nil
+}
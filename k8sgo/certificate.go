@@ -0,0 +1,159 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sgo
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var certificateResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// CertificateParameters is a struct for cert-manager certificate inputs
+type CertificateParameters struct {
+	CertificateMeta metav1.ObjectMeta
+	OwnerDef        metav1.OwnerReference
+	Namespace       string
+	SecretName      string
+	CommonName      string
+	DNSNames        []string
+	Usages          []string
+	IssuerName      string
+	IssuerKind      string
+	IssuerGroup     string
+}
+
+// CreateOrUpdateCertificate method will create or update cert-manager certificate
+func CreateOrUpdateCertificate(params CertificateParameters) error {
+	logger := LogGenerator(params.CertificateMeta.Name, params.Namespace, "Certificate")
+	certificateDef := generateCertificateDef(params)
+	storedCertificate, err := getCertificate(params.Namespace, params.CertificateMeta.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return createCertificate(params.Namespace, certificateDef)
+		}
+		return err
+	}
+	if reflect.DeepEqual(storedCertificate.Object["spec"], certificateDef.Object["spec"]) {
+		logger.Info("Certificate is already in-sync")
+		return nil
+	}
+	certificateDef.SetResourceVersion(storedCertificate.GetResourceVersion())
+	return updateCertificate(params.Namespace, certificateDef)
+}
+
+// IsCertificateReady is a method to check if cert-manager has issued the certificate
+func IsCertificateReady(namespace string, name string) (bool, error) {
+	certificate, err := getCertificate(namespace, name)
+	if err != nil {
+		return false, err
+	}
+	conditions, _, err := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if conditionMap["type"] == "Ready" && conditionMap["status"] == "True" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// generateCertificateDef is a method to generate cert-manager certificate definition
+func generateCertificateDef(params CertificateParameters) *unstructured.Unstructured {
+	var dnsNames, usages []interface{}
+	for _, dnsName := range params.DNSNames {
+		dnsNames = append(dnsNames, dnsName)
+	}
+	for _, usage := range params.Usages {
+		usages = append(usages, usage)
+	}
+	certificate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"secretName": params.SecretName,
+				"commonName": params.CommonName,
+				"dnsNames":   dnsNames,
+				"usages":     usages,
+				"privateKey": map[string]interface{}{
+					"algorithm": "RSA",
+					"encoding":  "PKCS8",
+					"size":      int64(2048),
+				},
+				"issuerRef": map[string]interface{}{
+					"name":  params.IssuerName,
+					"kind":  params.IssuerKind,
+					"group": params.IssuerGroup,
+				},
+			},
+		},
+	}
+	certificate.SetAPIVersion("cert-manager.io/v1")
+	certificate.SetKind("Certificate")
+	certificate.SetName(params.CertificateMeta.Name)
+	certificate.SetNamespace(params.Namespace)
+	certificate.SetLabels(params.CertificateMeta.Labels)
+	certificate.SetAnnotations(params.CertificateMeta.Annotations)
+	AddOwnerRefToObject(certificate, params.OwnerDef)
+	return certificate
+}
+
+// createCertificate is a method to create cert-manager certificate
+func createCertificate(namespace string, certificate *unstructured.Unstructured) error {
+	logger := LogGenerator(certificate.GetName(), namespace, "Certificate")
+	_, err := GenerateK8sDynamicClient().Resource(certificateResource).Namespace(namespace).Create(context.TODO(), certificate, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(err, "Certificate creation is failed")
+		return err
+	}
+	logger.Info("Certificate creation is successful")
+	return nil
+}
+
+// updateCertificate is a method to update cert-manager certificate
+func updateCertificate(namespace string, certificate *unstructured.Unstructured) error {
+	logger := LogGenerator(certificate.GetName(), namespace, "Certificate")
+	_, err := GenerateK8sDynamicClient().Resource(certificateResource).Namespace(namespace).Update(context.TODO(), certificate, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(err, "Certificate updation is failed")
+		return err
+	}
+	logger.Info("Certificate updation is successful")
+	return nil
+}
+
+// getCertificate is a method to get cert-manager certificate
+func getCertificate(namespace string, name string) (*unstructured.Unstructured, error) {
+	logger := LogGenerator(name, namespace, "Certificate")
+	certificateInfo, err := GenerateK8sDynamicClient().Resource(certificateResource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Info("Certificate get action is failed")
+		return nil, err
+	}
+	logger.Info("Certificate get action is successful")
+	return certificateInfo, nil
+}
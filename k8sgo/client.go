@@ -17,6 +17,7 @@ limitations under the License.
 package k8sgo
 
 import (
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -35,6 +36,19 @@ func GenerateK8sClient() *kubernetes.Clientset {
 	return clientset
 }
 
+// GenerateK8sDynamicClient create dynamic client for kubernetes
+func GenerateK8sDynamicClient() dynamic.Interface {
+	config, err := generateK8sConfig()
+	if err != nil {
+		panic(err.Error())
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		panic(err.Error())
+	}
+	return dynamicClient
+}
+
 // generateK8sConfig will load the kube config file
 func generateK8sConfig() (*rest.Config, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
@@ -39,7 +39,7 @@ type ConfigMapParameters struct {
 func CreateOrUpdateConfigMap(params ConfigMapParameters) error {
 	logger := LogGenerator(params.ConfigMapMeta.Name, params.Namespace, "ConfigMap")
 	configMapDef := generateConfigMap(params)
-	storedConfigMap, err := getConfigMap(params.ConfigMapMeta.Name, params.Namespace)
+	storedConfigMap, err := GetConfigMap(params.ConfigMapMeta.Name, params.Namespace)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(configMapDef); err != nil {
@@ -89,9 +89,21 @@ func createConfigMap(namespace string, configMap *corev1.ConfigMap) error {
 	return nil
 }
 
+// DeleteConfigMap is a method to delete configmap in Kubernetes
+func DeleteConfigMap(name, namespace string) error {
+	logger := LogGenerator(name, namespace, "ConfigMap")
+	err := GenerateK8sClient().CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "ConfigMap deletion is failed")
+		return err
+	}
+	logger.Info("ConfigMap deletion is successful")
+	return nil
+}
+
 //nolint:gosimple
-// getConfigMap is a method to check configmap exists
-func getConfigMap(name, namespace string) (*corev1.ConfigMap, error) {
+// GetConfigMap is a method to get configmap in Kubernetes
+func GetConfigMap(name, namespace string) (*corev1.ConfigMap, error) {
 	configMapInfo, err := GenerateK8sClient().CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
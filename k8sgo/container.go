@@ -21,27 +21,33 @@ import (
 )
 
 type ContainerParams struct {
-	Name           string
-	Image          string
-	Resources      *corev1.ResourceRequirements
-	InitResources  *corev1.ResourceRequirements
-	VolumeMount    *[]corev1.VolumeMount
-	EnvVar         []corev1.EnvVar
-	EnvVarFrom     []corev1.EnvFromSource
-	ReadinessProbe *corev1.Probe
-	LivenessProbe  *corev1.Probe
+	Name            string
+	Image           string
+	ImagePullPolicy corev1.PullPolicy
+	Command         []string
+	SecurityContext *corev1.SecurityContext
+	Resources       *corev1.ResourceRequirements
+	InitResources   *corev1.ResourceRequirements
+	VolumeMount     *[]corev1.VolumeMount
+	EnvVar          []corev1.EnvVar
+	EnvVarFrom      []corev1.EnvFromSource
+	ReadinessProbe  *corev1.Probe
+	LivenessProbe   *corev1.Probe
 }
 
 // generateContainerDef is a method to create container definition
 func generateContainerDef(params ContainerParams) []corev1.Container {
 	containerDef := []corev1.Container{
 		{
-			Name:           params.Name,
-			Image:          params.Image,
-			VolumeMounts:   *params.VolumeMount,
-			Env:            params.EnvVar,
-			LivenessProbe:  params.LivenessProbe,
-			ReadinessProbe: params.ReadinessProbe,
+			Name:            params.Name,
+			Image:           params.Image,
+			ImagePullPolicy: params.ImagePullPolicy,
+			Command:         params.Command,
+			SecurityContext: params.SecurityContext,
+			VolumeMounts:    *params.VolumeMount,
+			Env:             params.EnvVar,
+			LivenessProbe:   params.LivenessProbe,
+			ReadinessProbe:  params.ReadinessProbe,
 		},
 	}
 	if params.Resources != nil {
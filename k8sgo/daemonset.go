@@ -35,18 +35,22 @@ type DaemonSetParameters struct {
 	ContainerParams   ContainerParams
 	Labels            map[string]string
 	Annotations       map[string]string
+	PodAnnotations    map[string]string
 	NodeSelector      map[string]string
 	Affinity          *corev1.Affinity
 	Tolerations       *[]corev1.Toleration
 	PriorityClassName *string
 	SecurityContext   *corev1.PodSecurityContext
 	Volumes           *[]corev1.Volume
+	// ServiceAccountName is left empty for the default service account of the namespace
+	ServiceAccountName string
+	ImagePullSecrets   []corev1.LocalObjectReference
 }
 
 // CreateOrUpdateDaemonSet method will create or update DaemonSet
 func CreateOrUpdateDaemonSet(params DaemonSetParameters) error {
 	logger := LogGenerator(params.DaemonSetMeta.Name, params.Namespace, "DaemonSet")
-	storedDaemonSet, err := getDaemonSet(params.Namespace, params.DaemonSetMeta.Name)
+	storedDaemonSet, err := GetDaemonSet(params.Namespace, params.DaemonSetMeta.Name)
 	daemonSet := generateDaemonSet(params)
 	if err != nil {
 		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(daemonSet); err != nil {
@@ -103,12 +107,14 @@ func generateDaemonSet(params DaemonSetParameters) *appsv1.DaemonSet {
 		Spec: appsv1.DaemonSetSpec{
 			Selector: LabelSelectors(params.Labels),
 			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Labels: params.Labels},
+				ObjectMeta: metav1.ObjectMeta{Labels: params.Labels, Annotations: params.PodAnnotations},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: params.DaemonSetMeta.Name,
+					ServiceAccountName: params.ServiceAccountName,
 					Containers:         generateContainerDef(params.ContainerParams),
 					NodeSelector:       params.NodeSelector,
 					Affinity:           params.Affinity,
+					ImagePullSecrets:   params.ImagePullSecrets,
+					SecurityContext:    params.SecurityContext,
 				},
 			},
 		},
@@ -122,12 +128,15 @@ func generateDaemonSet(params DaemonSetParameters) *appsv1.DaemonSet {
 	if params.Tolerations != nil {
 		daemonSet.Spec.Template.Spec.Tolerations = *params.Tolerations
 	}
-	AddOwnerRefToObject(daemonSet, params.OwnerDef)
+	// daemonsets outside of the namespace of their owner can't reference it
+	if params.OwnerDef.Name != "" {
+		AddOwnerRefToObject(daemonSet, params.OwnerDef)
+	}
 	return daemonSet
 }
 
-// getDaemonSet is a method to get daemonset in Kubernetes
-func getDaemonSet(namespace string, daemonSet string) (*appsv1.DaemonSet, error) {
+// GetDaemonSet is a method to get daemonset in Kubernetes
+func GetDaemonSet(namespace string, daemonSet string) (*appsv1.DaemonSet, error) {
 	logger := LogGenerator(daemonSet, namespace, "DaemonSet")
 	daemonSetInfo, err := GenerateK8sClient().AppsV1().DaemonSets(namespace).Get(context.TODO(), daemonSet, metav1.GetOptions{})
 	if err != nil {
@@ -164,9 +173,24 @@ func updateDaemonSet(namespace string, daemonSet *appsv1.DaemonSet) error {
 
 // GetDaemonSetCount is a method to get running pods of daemonset
 func GetDaemonSetCount(namespace, name string) (*int32, error) {
-	daemonSet, err := getDaemonSet(namespace, name)
+	daemonSet, err := GetDaemonSet(namespace, name)
 	if err != nil {
 		return nil, err
 	}
 	return &daemonSet.Status.CurrentNumberScheduled, nil
 }
+
+// DeleteDaemonSet is a method to delete daemonset in Kubernetes
+func DeleteDaemonSet(namespace string, name string) error {
+	logger := LogGenerator(name, namespace, "DaemonSet")
+	err := GenerateK8sClient().AppsV1().DaemonSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		logger.Error(err, "DaemonSet deletion failed")
+		return err
+	}
+	logger.Info("DaemonSet deletion was successful")
+	return nil
+}
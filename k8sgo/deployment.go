@@ -36,18 +36,20 @@ type DeploymentParameters struct {
 	ContainerParams   ContainerParams
 	Labels            map[string]string
 	Annotations       map[string]string
+	PodAnnotations    map[string]string
 	NodeSelector      map[string]string
 	Affinity          *corev1.Affinity
 	Tolerations       *[]corev1.Toleration
 	PriorityClassName *string
 	SecurityContext   *corev1.PodSecurityContext
 	Volumes           *[]corev1.Volume
+	ImagePullSecrets  []corev1.LocalObjectReference
 }
 
 // CreateOrUpdateDeployment method will create or update deployment
 func CreateOrUpdateDeployment(params DeploymentParameters) error {
 	logger := LogGenerator(params.DeploymentMeta.Name, params.Namespace, "Deployment")
-	storedDeployment, err := getDeployment(params.Namespace, params.DeploymentMeta.Name)
+	storedDeployment, err := GetDeployment(params.Namespace, params.DeploymentMeta.Name)
 	deployment := generateDeploymentParams(params)
 	if err != nil {
 		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment); err != nil {
@@ -105,11 +107,13 @@ func generateDeploymentParams(params DeploymentParameters) *appsv1.Deployment {
 			Replicas: params.Replicas,
 			Selector: LabelSelectors(params.Labels),
 			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Labels: params.Labels},
+				ObjectMeta: metav1.ObjectMeta{Labels: params.Labels, Annotations: params.PodAnnotations},
 				Spec: corev1.PodSpec{
-					Containers:   generateContainerDef(params.ContainerParams),
-					NodeSelector: params.NodeSelector,
-					Affinity:     params.Affinity,
+					Containers:       generateContainerDef(params.ContainerParams),
+					NodeSelector:     params.NodeSelector,
+					Affinity:         params.Affinity,
+					ImagePullSecrets: params.ImagePullSecrets,
+					SecurityContext:  params.SecurityContext,
 				},
 			},
 		},
@@ -127,8 +131,8 @@ func generateDeploymentParams(params DeploymentParameters) *appsv1.Deployment {
 	return deployment
 }
 
-// getDeployment is a method to get deployment in Kubernetes
-func getDeployment(namespace string, deployment string) (*appsv1.Deployment, error) {
+// GetDeployment is a method to get deployment in Kubernetes
+func GetDeployment(namespace string, deployment string) (*appsv1.Deployment, error) {
 	logger := LogGenerator(deployment, namespace, "Deployment")
 	deploymentInfo, err := GenerateK8sClient().AppsV1().Deployments(namespace).Get(context.TODO(), deployment, metav1.GetOptions{})
 	if err != nil {
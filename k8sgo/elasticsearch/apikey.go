@@ -0,0 +1,239 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// ReconcileElasticsearchAPIKey is a method to issue an API key into a secret and rotate it when it is due
+func ReconcileElasticsearchAPIKey(cr *loggingv1beta1.Elasticsearch, apiKey *loggingv1beta1.ElasticsearchAPIKey) error {
+	logger := k8sgo.LogGenerator(apiKey.ObjectMeta.Name, apiKey.Namespace, "ElasticsearchAPIKey")
+	if cr.Spec.Security == nil {
+		return fmt.Errorf("security is not enabled on elasticsearch cluster %s", cr.ObjectMeta.Name)
+	}
+	if len(apiKey.Spec.RoleDescriptors) == 0 {
+		return fmt.Errorf("api key %s needs at least one role descriptor", apiKey.ObjectMeta.Name)
+	}
+	err := invalidateRotatedAPIKeys(cr, apiKey, false)
+	if err != nil {
+		return err
+	}
+
+	secretName := GetAPIKeySecretName(apiKey)
+	// secrets of other objects, like the generated passwords and certificates, must never be overwritten
+	secret, err := k8sgo.GetSecret(secretName, apiKey.Namespace)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && !k8sgo.IsSecretOwnedBy(secret, apiKey.UID) {
+		return &SecretConflictError{SecretName: secretName}
+	}
+	if apiKey.Status.SecretName != "" && apiKey.Status.SecretName != secretName {
+		err = k8sgo.DeleteOwnedSecret(apiKey.Status.SecretName, apiKey.Namespace, apiKey.UID)
+		if err != nil {
+			return err
+		}
+		// the key is issued again so that the new secret can be filled
+		rotateAPIKey(apiKey)
+	}
+	apiKey.Status.SecretName = secretName
+
+	err = adoptStoredAPIKey(cr, apiKey, secretName)
+	if err != nil {
+		return err
+	}
+	reason, err := getAPIKeyRotationReason(cr, apiKey)
+	if err != nil || reason == "" {
+		return err
+	}
+	logger.Info("Issuing api key", "reason", reason)
+	return issueAPIKey(cr, apiKey, secretName)
+}
+
+// InvalidateElasticsearchAPIKeys is a method to invalidate all keys of a resource before it is deleted
+func InvalidateElasticsearchAPIKeys(cr *loggingv1beta1.Elasticsearch, apiKey *loggingv1beta1.ElasticsearchAPIKey) error {
+	rotateAPIKey(apiKey)
+	return invalidateRotatedAPIKeys(cr, apiKey, true)
+}
+
+// GetAPIKeySecretName is a method to get the name of the secret which receives the API key
+func GetAPIKeySecretName(apiKey *loggingv1beta1.ElasticsearchAPIKey) string {
+	if apiKey.Spec.SecretName != "" {
+		return apiKey.Spec.SecretName
+	}
+	return fmt.Sprintf("%s-api-key", apiKey.ObjectMeta.Name)
+}
+
+// getAPIKeyRotationReason is a method to check if a new key has to be issued, an empty reason means the current key is kept
+func getAPIKeyRotationReason(cr *loggingv1beta1.Elasticsearch, apiKey *loggingv1beta1.ElasticsearchAPIKey) (string, error) {
+	currentKey := apiKey.Status.CurrentKey
+	if currentKey == nil {
+		return "no key issued", nil
+	}
+	if apiKey.Status.KeyGeneration != apiKey.Generation {
+		return "spec changed", nil
+	}
+	now := time.Now()
+	if currentKey.Expiration != nil && !now.Before(currentKey.Expiration.Time) {
+		return "key expired", nil
+	}
+	if apiKey.Spec.RotationInterval != nil && !now.Before(currentKey.CreationTime.Add(apiKey.Spec.RotationInterval.Duration)) {
+		return "rotation interval elapsed", nil
+	}
+	keyInfo, err := elasticgo.GetAPIKey(cr, currentKey.ID)
+	if err != nil {
+		return "", err
+	}
+	if keyInfo == nil || keyInfo.Invalidated {
+		// a key which was invalidated in elasticsearch can not be used during a grace period
+		apiKey.Status.CurrentKey = nil
+		return "key invalidated in elasticsearch", nil
+	}
+	return "", nil
+}
+
+// adoptStoredAPIKey is a method to take over a key which was written to the secret while the status could not be updated
+func adoptStoredAPIKey(cr *loggingv1beta1.Elasticsearch, apiKey *loggingv1beta1.ElasticsearchAPIKey, secretName string) error {
+	secret, err := k8sgo.GetSecret(secretName, apiKey.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the key can not be read again, a new one is issued for the secret
+			rotateAPIKey(apiKey)
+			return nil
+		}
+		return err
+	}
+	storedID := string(secret.Data["id"])
+	if apiKey.Status.CurrentKey != nil && apiKey.Status.CurrentKey.ID == storedID {
+		return nil
+	}
+	// the secret does not hold the current key, so the current key is not in use anymore
+	rotateAPIKey(apiKey)
+	if storedID == "" {
+		return nil
+	}
+	keyInfo, err := elasticgo.GetAPIKey(cr, storedID)
+	if err != nil {
+		return err
+	}
+	if keyInfo != nil && !keyInfo.Invalidated && keyInfo.Name == getAPIKeyName(apiKey) {
+		apiKey.Status.CurrentKey = generateAPIKeyStatus(keyInfo.ID, keyInfo.Name, keyInfo.Creation, keyInfo.Expiration)
+		apiKey.Status.KeyGeneration = apiKey.Generation
+	}
+	return nil
+}
+
+// issueAPIKey is a method to create a new key, write it to the secret and keep the previous key for the grace period
+func issueAPIKey(cr *loggingv1beta1.Elasticsearch, apiKey *loggingv1beta1.ElasticsearchAPIKey, secretName string) error {
+	keyRequest := elasticgo.ESAPIKeyRequest{
+		Name:       getAPIKeyName(apiKey),
+		Expiration: apiKey.Spec.Expiration,
+	}
+	keyRequest.RoleDescriptors = map[string]elasticgo.ESRole{}
+	for name, descriptor := range apiKey.Spec.RoleDescriptors {
+		keyRequest.RoleDescriptors[name] = generateRole(descriptor)
+	}
+	createdKey, err := elasticgo.CreateAPIKey(cr, keyRequest)
+	if err != nil {
+		return err
+	}
+	labels := map[string]string{
+		"app": apiKey.ObjectMeta.Name,
+	}
+	secretParams := k8sgo.SecretsParameters{
+		Name:        secretName,
+		OwnerDef:    k8sgo.ElasticsearchAPIKeyAsOwner(apiKey),
+		Namespace:   apiKey.Namespace,
+		SecretsMeta: k8sgo.GenerateObjectMetaInformation(secretName, apiKey.Namespace, labels, k8sgo.GenerateAnnotations()),
+		SecretData: map[string][]byte{
+			"id":      []byte(createdKey.ID),
+			"name":    []byte(createdKey.Name),
+			"api_key": []byte(createdKey.APIKey),
+			"encoded": []byte(createdKey.Encoded),
+		},
+	}
+	err = k8sgo.CreateOrUpdateSecret(apiKey.Namespace, k8sgo.GenerateSecret(secretParams))
+	if err != nil {
+		// the key was never handed out, so it does not need a grace period
+		_ = elasticgo.InvalidateAPIKey(cr, createdKey.ID)
+		return err
+	}
+	rotateAPIKey(apiKey)
+	apiKey.Status.CurrentKey = generateAPIKeyStatus(createdKey.ID, createdKey.Name, time.Now().UnixMilli(), createdKey.Expiration)
+	apiKey.Status.KeyGeneration = apiKey.Generation
+	return nil
+}
+
+// rotateAPIKey is a method to move the current key to the rotated keys, which are invalidated after the grace period
+func rotateAPIKey(apiKey *loggingv1beta1.ElasticsearchAPIKey) {
+	currentKey := apiKey.Status.CurrentKey
+	if currentKey == nil {
+		return
+	}
+	gracePeriod := time.Hour
+	if apiKey.Spec.GracePeriod != nil {
+		gracePeriod = apiKey.Spec.GracePeriod.Duration
+	}
+	invalidateAfter := metav1.NewTime(time.Now().Add(gracePeriod))
+	currentKey.InvalidateAfter = &invalidateAfter
+	apiKey.Status.RotatedKeys = append(apiKey.Status.RotatedKeys, *currentKey)
+	apiKey.Status.CurrentKey = nil
+}
+
+// invalidateRotatedAPIKeys is a method to invalidate the rotated keys whose grace period is over
+func invalidateRotatedAPIKeys(cr *loggingv1beta1.Elasticsearch, apiKey *loggingv1beta1.ElasticsearchAPIKey, all bool) error {
+	var rotatedKeys []loggingv1beta1.APIKeyStatus
+	for _, rotatedKey := range apiKey.Status.RotatedKeys {
+		if !all && rotatedKey.InvalidateAfter != nil && time.Now().Before(rotatedKey.InvalidateAfter.Time) {
+			rotatedKeys = append(rotatedKeys, rotatedKey)
+			continue
+		}
+		err := elasticgo.InvalidateAPIKey(cr, rotatedKey.ID)
+		if err != nil {
+			return err
+		}
+	}
+	apiKey.Status.RotatedKeys = rotatedKeys
+	return nil
+}
+
+// getAPIKeyName is a method to get the name of the keys of a resource in elasticsearch
+func getAPIKeyName(apiKey *loggingv1beta1.ElasticsearchAPIKey) string {
+	return fmt.Sprintf("%s-%s", apiKey.Namespace, apiKey.ObjectMeta.Name)
+}
+
+// generateAPIKeyStatus is a method to generate the status of a key from the epoch milliseconds returned by elasticsearch
+func generateAPIKeyStatus(id string, name string, creation int64, expiration int64) *loggingv1beta1.APIKeyStatus {
+	keyStatus := &loggingv1beta1.APIKeyStatus{
+		ID:           id,
+		Name:         name,
+		CreationTime: metav1.NewTime(time.UnixMilli(creation)),
+	}
+	if expiration > 0 {
+		expirationTime := metav1.NewTime(time.UnixMilli(expiration))
+		keyStatus.Expiration = &expirationTime
+	}
+	return keyStatus
+}
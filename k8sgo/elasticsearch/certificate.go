@@ -0,0 +1,217 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+)
+
+const (
+	certificateKeySize  = 2048
+	caCertValidity      = 5 * 365 * 24 * time.Hour
+	nodeCertValidity    = 365 * 24 * time.Hour
+	certificateKeyName  = "tls.key"
+	certificateCertName = "tls.crt"
+	certificateCAName   = "ca.crt"
+	certificateCAKey    = "ca.key"
+	elasticCertPath     = "/usr/share/elasticsearch/config/certs"
+)
+
+// elasticRoles is the list of node roles for which services are created
+var elasticRoles = []string{"master", "data", "client", "ingestion"}
+
+// generateCACertificate is a method to generate a self signed CA for an elasticsearch cluster
+func generateCACertificate(cr *loggingv1beta1.Elasticsearch) ([]byte, []byte, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, certificateKeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := generateSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:         fmt.Sprintf("%s-ca", cr.ObjectMeta.Name),
+			OrganizationalUnit: []string{cr.Namespace},
+			Organization:       []string{"logging-operator"},
+		},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(caCertValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodePrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), keyPEM, nil
+}
+
+// generateNodeCertificate is a method to generate elasticsearch node certificate signed by cluster CA
+func generateNodeCertificate(cr *loggingv1beta1.Elasticsearch, caCertPEM, caKeyPEM []byte) ([]byte, []byte, error) {
+	caCert, caKey, err := parseCertificateAuthority(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, certificateKeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := generateSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:         cr.ObjectMeta.Name,
+			OrganizationalUnit: []string{cr.Namespace},
+			Organization:       []string{"logging-operator"},
+		},
+		DNSNames:    getNodeCertificateDNSNames(cr),
+		NotBefore:   now.Add(-5 * time.Minute),
+		NotAfter:    now.Add(nodeCertValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if template.NotAfter.After(caCert.NotAfter) {
+		template.NotAfter = caCert.NotAfter
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &privateKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodePrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), keyPEM, nil
+}
+
+// getNodeCertificateDNSNames is a method to list the service and pod DNS names of an elasticsearch cluster
+func getNodeCertificateDNSNames(cr *loggingv1beta1.Elasticsearch) []string {
+	dnsNames := []string{"localhost"}
+	roles := append([]string{}, elasticRoles...)
+	for _, name := range getNodeSetNames(cr) {
+		if !containsString(roles, name) {
+			roles = append(roles, name)
+		}
+	}
+	for _, role := range roles {
+		for _, serviceName := range []string{fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role), fmt.Sprintf("%s-%s-headless", cr.ObjectMeta.Name, role)} {
+			dnsNames = append(dnsNames,
+				serviceName,
+				fmt.Sprintf("%s.%s", serviceName, cr.Namespace),
+				fmt.Sprintf("%s.%s.svc", serviceName, cr.Namespace),
+				fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, cr.Namespace),
+			)
+		}
+		headlessService := fmt.Sprintf("%s-%s-headless", cr.ObjectMeta.Name, role)
+		dnsNames = append(dnsNames,
+			fmt.Sprintf("*.%s", headlessService),
+			fmt.Sprintf("*.%s.%s", headlessService, cr.Namespace),
+			fmt.Sprintf("*.%s.%s.svc", headlessService, cr.Namespace),
+			fmt.Sprintf("*.%s.%s.svc.cluster.local", headlessService, cr.Namespace),
+		)
+	}
+	return dnsNames
+}
+
+// containsString is a method to check if a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCertificate is a method to decode the first certificate of a PEM bundle
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, fmt.Errorf("unable to decode certificate")
+	}
+	return x509.ParseCertificate(certBlock.Bytes)
+}
+
+// countCertificates is a method to count the certificates of a PEM bundle
+func countCertificates(certPEM []byte) int {
+	var count int
+	for {
+		var certBlock *pem.Block
+		certBlock, certPEM = pem.Decode(certPEM)
+		if certBlock == nil {
+			return count
+		}
+		count++
+	}
+}
+
+// parseCertificateAuthority is a method to decode PEM encoded CA certificate and key
+func parseCertificateAuthority(caCertPEM, caKeyPEM []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	caCert, err := parseCertificate(caCertPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, _ := pem.Decode(caKeyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("unable to decode CA private key")
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	caKey, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("CA private key is not a RSA key")
+	}
+	return caCert, caKey, nil
+}
+
+// encodePrivateKey is a method to PEM encode a private key in PKCS#8 format
+func encodePrivateKey(privateKey *rsa.PrivateKey) ([]byte, error) {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// generateSerialNumber is a method to generate random certificate serial number
+func generateSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
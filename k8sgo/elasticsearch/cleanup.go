@@ -0,0 +1,271 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// Volume claim delete policies of the elasticsearch spec
+const (
+	volumeClaimRetain                            = "Retain"
+	volumeClaimDeleteOnScaledownOnly             = "DeleteOnScaledownOnly"
+	volumeClaimDeleteOnScaledownAndClusterDelete = "DeleteOnScaledownAndClusterDeletion"
+)
+
+// Final snapshot states reported in the elasticsearch status
+const (
+	snapshotStateSuccess = "SUCCESS"
+	snapshotStatePartial = "PARTIAL"
+	snapshotStateFailed  = "FAILED"
+)
+
+// DeleteScaledDownVolumes is a method to delete the persistent volume claims of pods which were removed by a scale down
+func DeleteScaledDownVolumes(cr *loggingv1beta1.Elasticsearch) error {
+	if getVolumeClaimDeletePolicy(cr) == volumeClaimRetain {
+		return nil
+	}
+	claims, err := k8sgo.ListPersistentVolumeClaims(cr.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, role := range getNodeSetNames(cr) {
+		appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role)
+		stateful, err := k8sgo.GetStateFulSet(cr.Namespace, appName)
+		if err != nil {
+			// statefulsets are recreated during volume expansion
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		replicas := int32(1)
+		if stateful.Spec.Replicas != nil {
+			replicas = *stateful.Spec.Replicas
+		}
+		for _, claim := range claims {
+			ordinal, ok := getClaimOrdinal(appName, claim.Name)
+			if !ok || ordinal < replicas {
+				continue
+			}
+			// the claim is only released once the pod which used it is gone
+			_, err := k8sgo.GetPod(cr.Namespace, fmt.Sprintf("%s-%d", appName, ordinal))
+			if err == nil {
+				continue
+			}
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			err = k8sgo.DeletePersistentVolumeClaim(cr.Namespace, claim.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteRemovedNodeSets is a method to delete the resources of node sets which were removed from the spec once their nodes are gone
+func DeleteRemovedNodeSets(cr *loggingv1beta1.Elasticsearch) error {
+	removedNodeSets, err := listRemovedNodeSets(cr)
+	if err != nil {
+		return err
+	}
+	for _, nodeSet := range removedNodeSets {
+		appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, nodeSet.Name)
+		// the scale down drains the nodes and scales the statefulset down to zero first
+		if cr.Status.ScaleDown != nil && cr.Status.ScaleDown.Role == nodeSet.Name {
+			continue
+		}
+		stateful, err := k8sgo.GetStateFulSet(cr.Namespace, appName)
+		if err != nil {
+			return err
+		}
+		if stateful.Spec.Replicas == nil || *stateful.Spec.Replicas > 0 {
+			continue
+		}
+		pods, err := k8sgo.ListPods(cr.Namespace, getNodeSetLabels(cr, nodeSet.Name))
+		if err != nil {
+			return err
+		}
+		if len(pods) > 0 {
+			continue
+		}
+		if getVolumeClaimDeletePolicy(cr) != volumeClaimRetain {
+			claims, err := k8sgo.ListPersistentVolumeClaims(cr.Namespace)
+			if err != nil {
+				return err
+			}
+			for _, claim := range claims {
+				if _, ok := getClaimOrdinal(appName, claim.Name); !ok {
+					continue
+				}
+				err = k8sgo.DeletePersistentVolumeClaim(cr.Namespace, claim.Name)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		for _, serviceName := range []string{appName, fmt.Sprintf("%s-headless", appName)} {
+			err = k8sgo.DeleteService(cr.Namespace, serviceName)
+			if err != nil {
+				return err
+			}
+		}
+		err = k8sgo.DeleteConfigMap(getConfigMapName(cr, nodeSet.Name), cr.Namespace)
+		if err != nil {
+			return err
+		}
+		// the statefulset is deleted last, it is how the removed node set is found again when a deletion fails
+		err = k8sgo.DeleteStateFulSet(cr.Namespace, appName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TakeFinalSnapshot is a method to take the final snapshot before the cluster is deleted, it reports if the snapshot is done
+func TakeFinalSnapshot(cr *loggingv1beta1.Elasticsearch) (bool, error) {
+	if cr.Spec.FinalSnapshot == nil {
+		return true, nil
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	finalSnapshot := cr.Status.FinalSnapshot
+	if finalSnapshot == nil || finalSnapshot.Repository != cr.Spec.FinalSnapshot.Repository {
+		// the name is stored first, so that a retry checks the same snapshot instead of taking another one
+		cr.Status.FinalSnapshot = &loggingv1beta1.FinalSnapshotStatus{
+			Repository: cr.Spec.FinalSnapshot.Repository,
+			Name:       fmt.Sprintf("%s-final-%s", cr.ObjectMeta.Name, time.Now().UTC().Format("2006.01.02-15.04.05")),
+		}
+		return false, nil
+	}
+	state, err := elasticgo.GetSnapshotState(cr, finalSnapshot.Repository, finalSnapshot.Name)
+	if err != nil {
+		finalSnapshot.Message = err.Error()
+		return false, nil
+	}
+	finalSnapshot.State = state
+	switch state {
+	case "":
+		logger.Info("Taking final snapshot", "repository", finalSnapshot.Repository, "snapshot", finalSnapshot.Name)
+		err = elasticgo.CreateSnapshot(cr, finalSnapshot.Repository, finalSnapshot.Name, cr.Spec.FinalSnapshot.Indices)
+		if err != nil {
+			finalSnapshot.Message = err.Error()
+		}
+		return false, nil
+	case snapshotStateSuccess:
+		finalSnapshot.Message = ""
+		return true, nil
+	case snapshotStatePartial, snapshotStateFailed:
+		// deletion stays blocked until the final snapshot is removed from the spec
+		finalSnapshot.Message = "final snapshot did not complete, remove finalSnapshot from the spec to delete the cluster without it"
+		return false, nil
+	}
+	return false, nil
+}
+
+// IsFinalSnapshotPending is a method to check if a deleted cluster still has to take its final snapshot into a repository
+func IsFinalSnapshotPending(cr *loggingv1beta1.Elasticsearch, repository string) bool {
+	if cr.DeletionTimestamp == nil || cr.Spec.FinalSnapshot == nil || cr.Spec.FinalSnapshot.Repository != repository {
+		return false
+	}
+	return cr.Status.FinalSnapshot == nil || cr.Status.FinalSnapshot.State != snapshotStateSuccess
+}
+
+// CleanupElasticResources is a method to delete or keep the volumes and generated secrets of a deleted cluster
+func CleanupElasticResources(cr *loggingv1beta1.Elasticsearch) error {
+	// the sysctl daemonset may run in another namespace without an owner reference
+	namespace, name := getSysctlDaemonSetName(cr)
+	err := k8sgo.DeleteDaemonSet(namespace, name)
+	if err != nil {
+		return err
+	}
+	secretNames := getGeneratedSecretNames(cr)
+	if getVolumeClaimDeletePolicy(cr) != volumeClaimDeleteOnScaledownAndClusterDelete {
+		// retained volumes can only be used again with the passwords and certificates they were written with
+		for _, secretName := range secretNames {
+			err := k8sgo.ReleaseSecret(secretName, cr.Namespace, cr.UID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	claims, err := k8sgo.ListPersistentVolumeClaims(cr.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, role := range getNodeSetNames(cr) {
+		appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role)
+		for _, claim := range claims {
+			if _, ok := getClaimOrdinal(appName, claim.Name); !ok {
+				continue
+			}
+			err = k8sgo.DeletePersistentVolumeClaim(cr.Namespace, claim.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// secrets released by an earlier deletion with retained volumes have no owner anymore
+	for _, secretName := range secretNames {
+		err = k8sgo.DeleteSecret(secretName, cr.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getVolumeClaimDeletePolicy is a method to get the volume claim delete policy of a cluster
+func getVolumeClaimDeletePolicy(cr *loggingv1beta1.Elasticsearch) string {
+	if cr.Spec.VolumeClaimDeletePolicy == "" {
+		return volumeClaimRetain
+	}
+	return cr.Spec.VolumeClaimDeletePolicy
+}
+
+// getGeneratedSecretNames is a method to list the secrets which are generated by the operator for a cluster
+func getGeneratedSecretNames(cr *loggingv1beta1.Elasticsearch) []string {
+	var secretNames []string
+	for _, suffix := range []string{"password", "tls-ca", "tls-cert", "ca-cert", "sa-token", "snapshot-credentials"} {
+		secretNames = append(secretNames, fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, suffix))
+	}
+	return secretNames
+}
+
+// getClaimOrdinal is a method to get the pod ordinal of a claim which was created from the claim template of a node set
+func getClaimOrdinal(appName string, claimName string) (int32, bool) {
+	prefix := fmt.Sprintf("%s-%s-", appName, appName)
+	if !strings.HasPrefix(claimName, prefix) {
+		return 0, false
+	}
+	ordinal, err := strconv.ParseInt(strings.TrimPrefix(claimName, prefix), 10, 32)
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return int32(ordinal), true
+}
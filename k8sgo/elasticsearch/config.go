@@ -0,0 +1,199 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	configHashAnnotation = "logging.opstreelabs.in/config-hash"
+	configVolumeName     = "elasticsearch-config"
+	configFileName       = "elasticsearch.yml"
+	configMountPath      = "/usr/share/elasticsearch/config/elasticsearch.yml"
+)
+
+// listSettings are rendered as lists, the operator generates them as comma separated values
+var listSettings = map[string]bool{
+	"node.roles":                   true,
+	"discovery.seed_hosts":         true,
+	"cluster.initial_master_nodes": true,
+}
+
+// reservedSettings are owned by the operator without being generated as environment variables
+var reservedSettings = map[string]bool{
+	"path.data": true,
+}
+
+// SetupElasticConfig is a method to render the elasticsearch.yml of a node set into a configmap
+// The settings of the operator are moved out of the environment variables, the remaining variables are returned
+func SetupElasticConfig(cr *loggingv1beta1.Elasticsearch, nodeSet loggingv1beta1.NodeSet, envVars []corev1.EnvVar) (string, []corev1.EnvVar, error) {
+	config, remainingEnvVars, err := generateElasticConfig(nodeSet, envVars)
+	if err != nil {
+		return "", nil, err
+	}
+	configMapName := getConfigMapName(cr, nodeSet.Name)
+	configMapParams := k8sgo.ConfigMapParameters{
+		Name:           configMapName,
+		OwnerDef:       k8sgo.ElasticAsOwner(cr),
+		Namespace:      cr.Namespace,
+		ConfigMapMeta:  k8sgo.GenerateObjectMetaInformation(configMapName, cr.Namespace, getNodeSetLabels(cr, nodeSet.Name), k8sgo.GenerateAnnotations()),
+		ConfigMapKey:   configFileName,
+		ConfigMapValue: config,
+	}
+	err = k8sgo.CreateOrUpdateConfigMap(configMapParams)
+	if err != nil {
+		return "", nil, err
+	}
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:]), remainingEnvVars, nil
+}
+
+// generateElasticConfig is a method to merge the config of a node set with the settings of the operator
+func generateElasticConfig(nodeSet loggingv1beta1.NodeSet, envVars []corev1.EnvVar) (string, []corev1.EnvVar, error) {
+	settings, err := getUserSettings(nodeSet)
+	if err != nil {
+		return "", nil, err
+	}
+	var remainingEnvVars []corev1.EnvVar
+	operatorSettings := map[string]interface{}{}
+	for _, envVar := range envVars {
+		if !strings.Contains(envVar.Name, ".") {
+			remainingEnvVars = append(remainingEnvVars, envVar)
+			continue
+		}
+		if _, ok := settings[envVar.Name]; ok {
+			return "", nil, fmt.Errorf("setting %s of node set %s is managed by the operator", envVar.Name, nodeSet.Name)
+		}
+		// settings from secrets and pod fields can only be passed as environment variables
+		if envVar.ValueFrom != nil {
+			remainingEnvVars = append(remainingEnvVars, envVar)
+			continue
+		}
+		operatorSettings[envVar.Name] = getSettingValue(envVar.Name, envVar.Value)
+	}
+	for key := range settings {
+		if reservedSettings[key] {
+			return "", nil, fmt.Errorf("setting %s of node set %s is managed by the operator", key, nodeSet.Name)
+		}
+	}
+	for key, value := range operatorSettings {
+		settings[key] = value
+	}
+	config, err := yaml.Marshal(settings)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(config), remainingEnvVars, nil
+}
+
+// getUserSettings is a method to flatten the config of a node set into dotted setting names
+func getUserSettings(nodeSet loggingv1beta1.NodeSet) (map[string]interface{}, error) {
+	settings := map[string]interface{}{}
+	if nodeSet.Config == nil || len(nodeSet.Config.Raw) == 0 {
+		return settings, nil
+	}
+	var config map[string]interface{}
+	err := json.Unmarshal(nodeSet.Config.Raw, &config)
+	if err != nil {
+		return nil, fmt.Errorf("config of node set %s is not an object: %w", nodeSet.Name, err)
+	}
+	flattenSettings("", config, settings)
+	return settings, nil
+}
+
+// flattenSettings is a method to convert nested objects into dotted setting names, lists are kept as values
+func flattenSettings(prefix string, config map[string]interface{}, settings map[string]interface{}) {
+	for key, value := range config {
+		name := key
+		if prefix != "" {
+			name = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenSettings(name, nested, settings)
+			continue
+		}
+		settings[name] = value
+	}
+}
+
+// getSettingValue is a method to convert a generated setting into its yaml value
+func getSettingValue(name string, value string) interface{} {
+	if !listSettings[name] {
+		return value
+	}
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// getStoredListSetting is a method to read a list setting from the rendered elasticsearch.yml of a node set
+func getStoredListSetting(cr *loggingv1beta1.Elasticsearch, role string, name string) (string, bool) {
+	configMap, err := k8sgo.GetConfigMap(getConfigMapName(cr, role), cr.Namespace)
+	if err != nil {
+		return "", false
+	}
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configMap.Data[configFileName]), &settings); err != nil {
+		return "", false
+	}
+	values, ok := settings[name].([]interface{})
+	if !ok {
+		return "", false
+	}
+	var items []string
+	for _, value := range values {
+		items = append(items, fmt.Sprint(value))
+	}
+	return strings.Join(items, ","), true
+}
+
+// getConfigMapName is a method to get the name of the configmap which holds the elasticsearch.yml of a node set
+func getConfigMapName(cr *loggingv1beta1.Elasticsearch, role string) string {
+	return fmt.Sprintf("%s-%s-config", cr.ObjectMeta.Name, role)
+}
+
+// getConfigVolume is a method to mount the rendered elasticsearch.yml of a node set
+func getConfigVolume(cr *loggingv1beta1.Elasticsearch, role string) (corev1.Volume, corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: configVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: getConfigMapName(cr, role)},
+			},
+		},
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      configVolumeName,
+		MountPath: configMountPath,
+		SubPath:   configFileName,
+	}
+	return volume, volumeMount
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// IsIssuerEnabled is a method to check if certificates are issued by cert-manager
+func IsIssuerEnabled(cr *loggingv1beta1.Elasticsearch) bool {
+	return cr.Spec.Security != nil && cr.Spec.Security.IssuerRef != nil && len(getManagedCertificateLayers(cr)) > 0
+}
+
+// CreateElasticCertificates is a method to create cert-manager certificates for http and transport layer
+func CreateElasticCertificates(cr *loggingv1beta1.Elasticsearch) error {
+	issuerRef := cr.Spec.Security.IssuerRef
+	labels := map[string]string{
+		"app": cr.ObjectMeta.Name,
+	}
+	for _, layer := range getManagedCertificateLayers(cr) {
+		usages := []string{"digital signature", "key encipherment", "server auth"}
+		// transport certificates are presented by nodes when connecting to each other
+		if layer == "transport" {
+			usages = append(usages, "client auth")
+		}
+		certificateName := fmt.Sprintf("%s-%s-cert", cr.ObjectMeta.Name, layer)
+		certificateParams := k8sgo.CertificateParameters{
+			CertificateMeta: k8sgo.GenerateObjectMetaInformation(certificateName, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
+			OwnerDef:        k8sgo.ElasticAsOwner(cr),
+			Namespace:       cr.Namespace,
+			SecretName:      certificateName,
+			CommonName:      fmt.Sprintf("%s-master", cr.ObjectMeta.Name),
+			DNSNames:        getNodeCertificateDNSNames(cr),
+			Usages:          usages,
+			IssuerName:      issuerRef.Name,
+			IssuerKind:      issuerRef.Kind,
+			IssuerGroup:     issuerRef.Group,
+		}
+		if certificateParams.IssuerKind == "" {
+			certificateParams.IssuerKind = "Issuer"
+		}
+		if certificateParams.IssuerGroup == "" {
+			certificateParams.IssuerGroup = "cert-manager.io"
+		}
+		err := k8sgo.CreateOrUpdateCertificate(certificateParams)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsElasticCertificateReady is a method to check if cert-manager has issued all elasticsearch certificates
+func IsElasticCertificateReady(cr *loggingv1beta1.Elasticsearch) (bool, error) {
+	for _, layer := range getManagedCertificateLayers(cr) {
+		ready, err := k8sgo.IsCertificateReady(cr.Namespace, fmt.Sprintf("%s-%s-cert", cr.ObjectMeta.Name, layer))
+		if err != nil || !ready {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// getIssuedTLSStatus is a method to get the expiry of cert-manager issued certificates
+func getIssuedTLSStatus(cr *loggingv1beta1.Elasticsearch) (*loggingv1beta1.TLSStatus, error) {
+	layer := getManagedCertificateLayers(cr)[0]
+	certSecret, err := k8sgo.GetSecret(fmt.Sprintf("%s-%s-cert", cr.ObjectMeta.Name, layer), cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	nodeCert, err := parseCertificate(certSecret.Data[certificateCertName])
+	if err != nil {
+		return nil, err
+	}
+	tlsStatus := &loggingv1beta1.TLSStatus{
+		NotAfter:      &metav1.Time{Time: nodeCert.NotAfter},
+		RotationPhase: rotationPhaseValid,
+	}
+	// issuers like ACME do not publish their CA in the secret
+	if caCert, err := parseCertificate(certSecret.Data[certificateCAName]); err == nil {
+		tlsStatus.CANotAfter = &metav1.Time{Time: caCert.NotAfter}
+	}
+	return tlsStatus, nil
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+const (
+	keystoreVolumeName     = "keystore-volume"
+	keystoreMountPath      = "/tmp/keystore"
+	keystoreSecretsPath    = "/tmp/keystoreSecrets"
+	keystoreHashVolumeName = "keystore-hash"
+	keystoreHashMountPath  = "/tmp/keystoreHash"
+	keystoreReloaderName   = "keystore-reloader"
+	// keystoreHashAnnotation is set on the pods to the hash of the secure settings the reloader has to build
+	keystoreHashAnnotation = "logging.opstreelabs.in/keystore-hash"
+	// keystoreRestartHashAnnotation restarts the pods when secure settings change which can not be reloaded
+	keystoreRestartHashAnnotation = "logging.opstreelabs.in/keystore-restart-hash"
+	// keystoreBuiltMessage is logged by the reloader as its last line, followed by the hash of the built keystore
+	keystoreBuiltMessage = "Keystore was built from secure settings "
+)
+
+// reloadableSettingPrefixes are the secure settings which elasticsearch applies on _nodes/reload_secure_settings
+var reloadableSettingPrefixes = []string{"s3.client.", "gcs.client.", "azure.client.", "xpack.notification."}
+
+// keystoreFunctions hash the mounted secrets in the same way as getKeystoreHashes and build the keystore in place
+const keystoreFunctions = `set -euo pipefail
+keystore_files() {
+  ls -d ` + keystoreSecretsPath + `/*/* 2>/dev/null | LC_ALL=C sort || true
+}
+keystore_hash() {
+  for i in $(keystore_files); do
+    printf '%s\n' "${i#` + keystoreSecretsPath + `/}"
+    cat "$i"
+  done | sha256sum | cut -d ' ' -f 1
+}
+build_keystore() {
+  conf=$(mktemp -d)
+  cp -r /usr/share/elasticsearch/config/. "$conf"
+  rm -f "$conf/elasticsearch.keystore"
+  ES_PATH_CONF="$conf" elasticsearch-keystore create
+  for i in $(keystore_files); do
+    key=$(basename $i)
+    echo "Adding file $i to keystore key $key"
+    ES_PATH_CONF="$conf" elasticsearch-keystore add-file "$key" "$i"
+  done
+  # Add the bootstrap password since otherwise the Elasticsearch entrypoint tries to do this on startup
+  if [ ! -z ${ELASTIC_PASSWORD+x} ]; then
+    echo 'Adding env $ELASTIC_PASSWORD to keystore as key bootstrap.password'
+    echo "$ELASTIC_PASSWORD" | ES_PATH_CONF="$conf" elasticsearch-keystore add -x bootstrap.password
+  fi
+  # the keystore is written in place, elasticsearch mounts the file and would not see a replaced one
+  cat "$conf/elasticsearch.keystore" > ` + keystoreMountPath + `/elasticsearch.keystore
+  rm -rf "$conf"
+}
+`
+
+// keystoreInitScript builds the keystore before elasticsearch starts
+const keystoreInitScript = keystoreFunctions + `hash=$(keystore_hash)
+build_keystore
+echo "$hash" > ` + keystoreMountPath + `/hash
+echo "` + keystoreBuiltMessage + `$hash"`
+
+// keystoreReloaderScript rebuilds the keystore once the mounted secrets match the hash published by the operator
+const keystoreReloaderScript = keystoreFunctions + `echo "` + keystoreBuiltMessage + `$(cat ` + keystoreMountPath + `/hash)"
+while true; do
+  sleep 10
+  desired=$(cat ` + keystoreHashMountPath + `/hash 2>/dev/null || true)
+  if [ -z "$desired" ] || [ "$desired" = "$(cat ` + keystoreMountPath + `/hash)" ]; then
+    continue
+  fi
+  # secrets and annotations are synced into the pod independently
+  hash=$(keystore_hash)
+  if [ "$hash" != "$desired" ]; then
+    continue
+  fi
+  build_keystore
+  echo "$hash" > ` + keystoreMountPath + `/hash
+  echo "` + keystoreBuiltMessage + `$hash"
+done`
+
+// ReconcileElasticKeystore is a method to rebuild the keystore of the running pods when secure settings changed and reload them in elasticsearch
+func ReconcileElasticKeystore(cr *loggingv1beta1.Elasticsearch) error {
+	if !isKeystoreEnabled(cr) {
+		cr.Status.Keystore = nil
+		return nil
+	}
+	keystoreHash, _, err := getKeystoreHashes(cr)
+	if err != nil {
+		return err
+	}
+	status := cr.Status.Keystore
+	if status == nil {
+		status = &loggingv1beta1.KeystoreStatus{}
+	}
+	var pendingPods []string
+	for _, role := range getNodeSetNames(cr) {
+		pods, err := k8sgo.ListPods(cr.Namespace, getNodeSetLabels(cr, role))
+		if err != nil {
+			return err
+		}
+		for i := range pods {
+			pod := &pods[i]
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			// pods which published the hash before and built it are only checked again after a change
+			if pod.Annotations[keystoreHashAnnotation] == keystoreHash && status.ReloadedHash == keystoreHash && !containsString(status.PendingPods, pod.Name) {
+				continue
+			}
+			if pod.Annotations[keystoreHashAnnotation] != keystoreHash {
+				err = k8sgo.AnnotatePod(pod, keystoreHashAnnotation, keystoreHash)
+				if err != nil {
+					return err
+				}
+			}
+			builtHash, err := getBuiltKeystoreHash(pod)
+			if err != nil {
+				return err
+			}
+			if builtHash != keystoreHash {
+				pendingPods = append(pendingPods, pod.Name)
+			}
+		}
+	}
+	if len(pendingPods) > 0 {
+		sort.Strings(pendingPods)
+		status.PendingPods = pendingPods
+		cr.Status.Keystore = status
+		return nil
+	}
+	// pods which caught up after the last reload started elasticsearch with an older keystore
+	if status.ReloadedHash != keystoreHash || len(status.PendingPods) > 0 {
+		err = elasticgo.ReloadSecureSettings(cr)
+		if err != nil {
+			return err
+		}
+		now := metav1.Now()
+		status.ReloadedHash = keystoreHash
+		status.ReloadTime = &now
+	}
+	status.PendingPods = nil
+	cr.Status.Keystore = status
+	return nil
+}
+
+// getBuiltKeystoreHash is a method to read the hash of the keystore which the reloader of a pod built last, it is empty while the reloader is not running
+func getBuiltKeystoreHash(pod *corev1.Pod) (string, error) {
+	running := false
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == keystoreReloaderName && containerStatus.State.Running != nil {
+			running = true
+		}
+	}
+	if !running {
+		return "", nil
+	}
+	logs, err := k8sgo.GetPodLogs(pod.Namespace, pod.Name, keystoreReloaderName, 1)
+	if err != nil {
+		return "", err
+	}
+	lastLine := strings.TrimSpace(logs)
+	if !strings.HasPrefix(lastLine, keystoreBuiltMessage) {
+		return "", nil
+	}
+	return strings.TrimPrefix(lastLine, keystoreBuiltMessage), nil
+}
+
+// getKeystoreSecrets is a method to list the user defined and operator managed secrets which are added to the keystore
+func getKeystoreSecrets(cr *loggingv1beta1.Elasticsearch) []loggingv1beta1.KeystoreSecret {
+	var secrets []loggingv1beta1.KeystoreSecret
+	if cr.Spec.ESKeystoreSecret != nil {
+		secrets = append(secrets, loggingv1beta1.KeystoreSecret{SecretName: *cr.Spec.ESKeystoreSecret})
+	}
+	secrets = append(secrets, cr.Spec.Keystore...)
+	secrets = append(secrets, getTLSKeystoreSecrets(cr)...)
+	if _, err := k8sgo.GetSecret(getSnapshotCredentialsSecretName(cr), cr.Namespace); err == nil {
+		secrets = append(secrets, loggingv1beta1.KeystoreSecret{SecretName: getSnapshotCredentialsSecretName(cr)})
+	}
+	return secrets
+}
+
+// isKeystoreEnabled is a method to check if the pods need a keystore
+func isKeystoreEnabled(cr *loggingv1beta1.Elasticsearch) bool {
+	return len(getKeystoreSecrets(cr)) > 0
+}
+
+// getKeystoreHashes is a method to hash all secure settings and the ones which need a restart to be applied
+func getKeystoreHashes(cr *loggingv1beta1.Elasticsearch) (string, string, error) {
+	files := map[string][]byte{}
+	settingSecrets := map[string]string{}
+	for count, keystoreSecret := range getKeystoreSecrets(cr) {
+		secret, err := k8sgo.GetSecret(keystoreSecret.SecretName, cr.Namespace)
+		if err != nil {
+			return "", "", err
+		}
+		settings := map[string][]byte{}
+		if len(keystoreSecret.Items) == 0 {
+			for key, value := range secret.Data {
+				settings[key] = value
+			}
+		}
+		for _, item := range keystoreSecret.Items {
+			value, ok := secret.Data[item.Key]
+			if !ok {
+				return "", "", fmt.Errorf("secret %s has no key %s", keystoreSecret.SecretName, item.Key)
+			}
+			settings[getKeystoreSetting(item)] = value
+		}
+		for setting, value := range settings {
+			if otherSecret, ok := settingSecrets[setting]; ok {
+				return "", "", fmt.Errorf("secure setting %s is defined by secrets %s and %s", setting, otherSecret, keystoreSecret.SecretName)
+			}
+			settingSecrets[setting] = keystoreSecret.SecretName
+			files[fmt.Sprintf("%s/%s", getKeystoreSecretDir(count, keystoreSecret), setting)] = value
+		}
+	}
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	keystoreHash := sha256.New()
+	restartHash := sha256.New()
+	for _, path := range paths {
+		keystoreHash.Write([]byte(path + "\n"))
+		keystoreHash.Write(files[path])
+		if !isReloadableSetting(path[strings.LastIndex(path, "/")+1:]) {
+			restartHash.Write([]byte(path + "\n"))
+			restartHash.Write(files[path])
+		}
+	}
+	return hex.EncodeToString(keystoreHash.Sum(nil)), hex.EncodeToString(restartHash.Sum(nil)), nil
+}
+
+// isReloadableSetting is a method to check if elasticsearch applies a secure setting without restart
+func isReloadableSetting(setting string) bool {
+	for _, prefix := range reloadableSettingPrefixes {
+		if strings.HasPrefix(setting, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getKeystoreSetting is a method to get the secure setting a key of a secret is added as
+func getKeystoreSetting(item loggingv1beta1.KeystoreItem) string {
+	if item.Setting != "" {
+		return item.Setting
+	}
+	return item.Key
+}
+
+// getKeystoreSecretDir is a method to get the directory below the keystore secrets path a secret is mounted to
+func getKeystoreSecretDir(count int, keystoreSecret loggingv1beta1.KeystoreSecret) string {
+	return fmt.Sprintf("%d-%s", count, keystoreSecret.SecretName)
+}
+
+// getKeystoreVolumeMounts is a method to mount the keystore and its secrets into the keystore containers
+func getKeystoreVolumeMounts(cr *loggingv1beta1.Elasticsearch) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      keystoreVolumeName,
+			MountPath: keystoreMountPath,
+		},
+	}
+	for count, keystoreSecret := range getKeystoreSecrets(cr) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      fmt.Sprintf("keystore-secret-%d", count),
+			MountPath: fmt.Sprintf("%s/%s", keystoreSecretsPath, getKeystoreSecretDir(count, keystoreSecret)),
+		})
+	}
+	return volumeMounts
+}
+
+// getKeystoreInitContainers is a method to build the keystore from the secrets before elasticsearch starts
+func getKeystoreInitContainers(cr *loggingv1beta1.Elasticsearch, role string) []corev1.Container {
+	if !isKeystoreEnabled(cr) {
+		return nil
+	}
+	return []corev1.Container{
+		{
+			Name:            "keystore",
+			Image:           getElasticImage(cr, role),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(getNodeSetKubernetesConfig(cr, role)),
+			Command:         []string{"bash", "-c", keystoreInitScript},
+			VolumeMounts:    getKeystoreVolumeMounts(cr),
+		},
+	}
+}
+
+// getKeystoreSidecarContainers is a method to rebuild the keystore of a running pod when its secrets change
+func getKeystoreSidecarContainers(cr *loggingv1beta1.Elasticsearch, role string) []corev1.Container {
+	if !isKeystoreEnabled(cr) {
+		return nil
+	}
+	volumeMounts := append(getKeystoreVolumeMounts(cr), corev1.VolumeMount{
+		Name:      keystoreHashVolumeName,
+		MountPath: keystoreHashMountPath,
+	})
+	return []corev1.Container{
+		{
+			Name:            keystoreReloaderName,
+			Image:           getElasticImage(cr, role),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(getNodeSetKubernetesConfig(cr, role)),
+			Command:         []string{"bash", "-c", keystoreReloaderScript},
+			VolumeMounts:    volumeMounts,
+		},
+	}
+}
+
+// getKeystoreVolumes is a method to define the keystore, its secrets and the hash published by the operator as volumes
+func getKeystoreVolumes(cr *loggingv1beta1.Elasticsearch) []corev1.Volume {
+	keystoreSecrets := getKeystoreSecrets(cr)
+	if len(keystoreSecrets) == 0 {
+		return nil
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: keystoreVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: keystoreHashVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				DownwardAPI: &corev1.DownwardAPIVolumeSource{
+					Items: []corev1.DownwardAPIVolumeFile{
+						{
+							Path: "hash",
+							FieldRef: &corev1.ObjectFieldSelector{
+								FieldPath: fmt.Sprintf("metadata.annotations['%s']", keystoreHashAnnotation),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for count, keystoreSecret := range keystoreSecrets {
+		var items []corev1.KeyToPath
+		for _, item := range keystoreSecret.Items {
+			items = append(items, corev1.KeyToPath{Key: item.Key, Path: getKeystoreSetting(item)})
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: fmt.Sprintf("keystore-secret-%d", count),
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: keystoreSecret.SecretName,
+					Items:      items,
+				},
+			},
+		})
+	}
+	return volumes
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// nodeRolesAnnotation keeps the node.roles setting of a node set on its statefulset
+const nodeRolesAnnotation = "logging.opstreelabs.in/node-roles"
+
+// GetNodeSets is a method to list all node sets of the cluster including the shorthand node groups
+func GetNodeSets(cr *loggingv1beta1.Elasticsearch) []loggingv1beta1.NodeSet {
+	var nodeSets []loggingv1beta1.NodeSet
+	masterRoles := []loggingv1beta1.NodeRole{"master"}
+	if cr.Spec.ESData == nil && !hasDataNodeSet(cr.Spec.NodeSets) {
+		masterRoles = append(masterRoles, "data")
+	}
+	shorthandNodeSets := []struct {
+		name       string
+		roles      []loggingv1beta1.NodeRole
+		nodeConfig *loggingv1beta1.NodeSpecificConfig
+	}{
+		{name: "master", roles: masterRoles, nodeConfig: cr.Spec.ESMaster},
+		{name: "data", roles: []loggingv1beta1.NodeRole{"data"}, nodeConfig: cr.Spec.ESData},
+		{name: "ingestion", roles: []loggingv1beta1.NodeRole{"ingest"}, nodeConfig: cr.Spec.ESIngestion},
+		{name: "client", roles: []loggingv1beta1.NodeRole{"data_content"}, nodeConfig: cr.Spec.ESClient},
+	}
+	for _, shorthand := range shorthandNodeSets {
+		if shorthand.nodeConfig != nil {
+			nodeSets = append(nodeSets, loggingv1beta1.NodeSet{
+				Name:               shorthand.name,
+				Roles:              shorthand.roles,
+				NodeSpecificConfig: *shorthand.nodeConfig,
+			})
+		}
+	}
+	return append(nodeSets, cr.Spec.NodeSets...)
+}
+
+// ValidateElasticNodeSets is a method to check that the cluster has master eligible nodes to form a cluster and serve the operator
+func ValidateElasticNodeSets(cr *loggingv1beta1.Elasticsearch) error {
+	for _, nodeSet := range GetNodeSets(cr) {
+		if hasNodeRole(nodeSet, "master") {
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster %s needs esMaster or a node set with the master role", cr.ObjectMeta.Name)
+}
+
+// SetupElasticSearchNodeSet is a method to setup the statefulset of an elasticsearch node set
+func SetupElasticSearchNodeSet(cr *loggingv1beta1.Elasticsearch, nodeSet loggingv1beta1.NodeSet) error {
+	if nodeSet.Storage == nil {
+		return fmt.Errorf("storage is not defined for node set %s", nodeSet.Name)
+	}
+	nodeParams := nodeSet.NodeSpecificConfig
+	envVars := generateEnvVariables(cr, nodeParams)
+	if hasNodeRole(nodeSet, "master") {
+		envVars = append(envVars, corev1.EnvVar{Name: "cluster.initial_master_nodes", Value: getInitialMasterNodes(cr)})
+	}
+	envVars = append(envVars, corev1.EnvVar{Name: "discovery.seed_hosts", Value: getSeedHosts(cr)})
+	envVars = append(envVars, corev1.EnvVar{Name: "network.host", Value: "0.0.0.0"})
+	envVars = append(envVars, corev1.EnvVar{Name: "cluster.name", Value: cr.Spec.ClusterName})
+	envVars = append(envVars, corev1.EnvVar{Name: "node.roles", Value: getNodeRoles(nodeSet)})
+	for key, value := range nodeSet.NodeAttributes {
+		// the zone attribute is owned by zone awareness when it is enabled
+		if isZoneAwarenessEnabled(cr) && key == zoneAttribute {
+			continue
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("node.attr.%s", key), Value: value})
+	}
+
+	envVars = append(envVars, getTLSEnvVariables(cr)...)
+	envVars = append(envVars, getZoneEnvVariables(cr)...)
+	envVars = append(envVars, getVMMaxMapCountEnvVariables(cr)...)
+	snapshotEnvVars, err := getSnapshotClientEnvVariables(cr)
+	if err != nil {
+		return err
+	}
+	envVars = append(envVars, snapshotEnvVars...)
+	sort.SliceStable(envVars, func(i, j int) bool {
+		return envVars[i].Name < envVars[j].Name
+	})
+	configHash, envVars, err := SetupElasticConfig(cr, nodeSet, envVars)
+	if err != nil {
+		return err
+	}
+	err = CreateElasticsearchStatefulSet(cr, &nodeParams, nodeSet.Name, envVars, configHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getNodeRoles is a method to generate the node.roles setting of a node set
+func getNodeRoles(nodeSet loggingv1beta1.NodeSet) string {
+	var roles []string
+	for _, role := range nodeSet.Roles {
+		roles = append(roles, string(role))
+	}
+	return strings.Join(roles, ",")
+}
+
+// getSeedHosts is a method to list the headless services of all master node sets
+func getSeedHosts(cr *loggingv1beta1.Elasticsearch) string {
+	var seedHosts []string
+	for _, nodeSet := range GetNodeSets(cr) {
+		if hasNodeRole(nodeSet, "master") {
+			seedHosts = append(seedHosts, fmt.Sprintf("%s-%s-headless", cr.ObjectMeta.Name, nodeSet.Name))
+		}
+	}
+	return strings.Join(seedHosts, ",")
+}
+
+// getInitialMasterNodes is a method to get the masters which bootstrap the cluster
+func getInitialMasterNodes(cr *loggingv1beta1.Elasticsearch) string {
+	var nodes []string
+	for _, nodeSet := range GetNodeSets(cr) {
+		if !hasNodeRole(nodeSet, "master") {
+			continue
+		}
+		// the setting is only used for bootstrapping, changing it on scaling would restart all masters
+		if initialMasterNodes, ok := getStoredListSetting(cr, nodeSet.Name, "cluster.initial_master_nodes"); ok {
+			return initialMasterNodes
+		}
+		// clusters created before elasticsearch.yml was rendered keep the setting in the environment variables
+		appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, nodeSet.Name)
+		var containers []corev1.Container
+		stateful, err := k8sgo.GetStateFulSet(cr.Namespace, appName)
+		if err == nil {
+			containers = stateful.Spec.Template.Spec.Containers
+		} else if pod, err := k8sgo.GetPod(cr.Namespace, fmt.Sprintf("%s-0", appName)); err == nil {
+			// pods are orphaned while the statefulset is recreated for volume expansion
+			containers = pod.Spec.Containers
+		}
+		for _, container := range containers {
+			for _, envVar := range container.Env {
+				if envVar.Name == "cluster.initial_master_nodes" {
+					return envVar.Value
+				}
+			}
+		}
+	}
+	for _, nodeSet := range GetNodeSets(cr) {
+		if !hasNodeRole(nodeSet, "master") || hasNodeRole(nodeSet, "voting_only") {
+			continue
+		}
+		for count := 0; count < int(getNodeSetReplicas(cr, nodeSet.Name)); count++ {
+			nodes = append(nodes, fmt.Sprintf("%s-%s-%s", cr.ObjectMeta.Name, nodeSet.Name, strconv.Itoa(count)))
+		}
+	}
+	return strings.Join(nodes, ",")
+}
+
+// getNodeSet is a method to find a node set by name
+func getNodeSet(cr *loggingv1beta1.Elasticsearch, name string) *loggingv1beta1.NodeSet {
+	for _, nodeSet := range GetNodeSets(cr) {
+		if nodeSet.Name == name {
+			return &nodeSet
+		}
+	}
+	return nil
+}
+
+// listRemovedNodeSets is a method to find the node sets which were removed from the spec while their statefulsets still exist
+func listRemovedNodeSets(cr *loggingv1beta1.Elasticsearch) ([]loggingv1beta1.NodeSet, error) {
+	statefulSets, err := k8sgo.ListStateFulSets(cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return getRemovedNodeSets(cr, statefulSets), nil
+}
+
+// getRemovedNodeSets is a method to get the node sets of the owned statefulsets which are not part of the spec anymore
+func getRemovedNodeSets(cr *loggingv1beta1.Elasticsearch, statefulSets []appsv1.StatefulSet) []loggingv1beta1.NodeSet {
+	var nodeSets []loggingv1beta1.NodeSet
+	prefix := fmt.Sprintf("%s-", cr.ObjectMeta.Name)
+	for _, stateful := range statefulSets {
+		if !strings.HasPrefix(stateful.Name, prefix) || !isOwnedByElastic(cr, stateful.OwnerReferences) {
+			continue
+		}
+		name := strings.TrimPrefix(stateful.Name, prefix)
+		if getNodeSet(cr, name) != nil {
+			continue
+		}
+		nodeSets = append(nodeSets, loggingv1beta1.NodeSet{Name: name, Roles: getStoredNodeRoles(cr, stateful)})
+	}
+	return nodeSets
+}
+
+// getStoredNodeRoles is a method to read the roles of a node set from its statefulset or its rendered elasticsearch.yml
+func getStoredNodeRoles(cr *loggingv1beta1.Elasticsearch, stateful appsv1.StatefulSet) []loggingv1beta1.NodeRole {
+	nodeRoles, ok := stateful.Annotations[nodeRolesAnnotation]
+	if !ok {
+		nodeRoles, ok = getStoredListSetting(cr, strings.TrimPrefix(stateful.Name, fmt.Sprintf("%s-", cr.ObjectMeta.Name)), "node.roles")
+	}
+	// nodes with unknown roles are drained and removed from the voting configuration before they are stopped
+	if !ok {
+		return []loggingv1beta1.NodeRole{"master", "data"}
+	}
+	var roles []loggingv1beta1.NodeRole
+	for _, role := range strings.Split(nodeRoles, ",") {
+		if role != "" {
+			roles = append(roles, loggingv1beta1.NodeRole(role))
+		}
+	}
+	return roles
+}
+
+// getNodeSetNames is a method to list the names of all node sets
+func getNodeSetNames(cr *loggingv1beta1.Elasticsearch) []string {
+	var names []string
+	for _, nodeSet := range GetNodeSets(cr) {
+		names = append(names, nodeSet.Name)
+	}
+	return names
+}
+
+// getNodeSetReplicas is a method to get the replicas of a node set
+func getNodeSetReplicas(cr *loggingv1beta1.Elasticsearch, name string) int32 {
+	nodeSet := getNodeSet(cr, name)
+	if nodeSet == nil {
+		return 0
+	}
+	if nodeSet.Replicas == nil {
+		return 1
+	}
+	return *nodeSet.Replicas
+}
+
+// getNodeSetKubernetesConfig is a method to get the kubernetes config of a node set
+func getNodeSetKubernetesConfig(cr *loggingv1beta1.Elasticsearch, name string) *loggingv1beta1.KubernetesConfig {
+	nodeSet := getNodeSet(cr, name)
+	if nodeSet == nil {
+		return nil
+	}
+	return nodeSet.KubernetesConfig
+}
+
+// getElasticImage is a method to get the image of the elasticsearch containers of a node set
+func getElasticImage(cr *loggingv1beta1.Elasticsearch, name string) string {
+	return k8sgo.GetElasticsearchImage(getNodeSetKubernetesConfig(cr, name), getElasticVersion(cr))
+}
+
+// hasNodeRole is a method to check if the nodes of a node set have a role
+func hasNodeRole(nodeSet loggingv1beta1.NodeSet, role loggingv1beta1.NodeRole) bool {
+	for _, nodeRole := range nodeSet.Roles {
+		if nodeRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isDataNodeSet is a method to check if the nodes of a node set hold shards
+func isDataNodeSet(nodeSet loggingv1beta1.NodeSet) bool {
+	for _, nodeRole := range nodeSet.Roles {
+		if strings.HasPrefix(string(nodeRole), "data") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDataNodeSet is a method to check if any of the node sets holds shards
+func hasDataNodeSet(nodeSets []loggingv1beta1.NodeSet) bool {
+	for _, nodeSet := range nodeSets {
+		if isDataNodeSet(nodeSet) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// ReconcileElasticPodDisruptionBudgets is a method to limit the pods of every node set which can be evicted at once
+func ReconcileElasticPodDisruptionBudgets(cr *loggingv1beta1.Elasticsearch) error {
+	cr.Status.RestrictedDisruptionBudgets = nil
+	appNames := map[string]bool{}
+	for _, nodeSet := range GetNodeSets(cr) {
+		appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, nodeSet.Name)
+		appNames[appName] = true
+		budget := nodeSet.PodDisruptionBudget
+		if budget != nil && budget.Enabled != nil && !*budget.Enabled {
+			err := k8sgo.DeletePodDisruptionBudget(cr.Namespace, appName)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		labels := getNodeSetLabels(cr, nodeSet.Name)
+		minAvailable, maxUnavailable, err := getNodeSetDisruptionBudget(cr, nodeSet)
+		if err != nil {
+			return err
+		}
+		if minAvailable == nil && maxUnavailable == nil {
+			err = k8sgo.DeletePodDisruptionBudget(cr.Namespace, appName)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if isDisruptionBudgetRestricted(cr, nodeSet) {
+			cr.Status.RestrictedDisruptionBudgets = append(cr.Status.RestrictedDisruptionBudgets, nodeSet.Name)
+		}
+		pdbParams := k8sgo.PodDisruptionBudgetParameters{
+			PDBMeta:        k8sgo.GenerateObjectMetaInformation(appName, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
+			OwnerDef:       k8sgo.ElasticAsOwner(cr),
+			Namespace:      cr.Namespace,
+			Labels:         labels,
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+		}
+		err = k8sgo.CreateOrUpdatePodDisruptionBudget(pdbParams)
+		if err != nil {
+			return err
+		}
+	}
+	return deleteRemovedPodDisruptionBudgets(cr, appNames)
+}
+
+// deleteRemovedPodDisruptionBudgets is a method to delete the pod disruption budgets of node sets which were removed from the spec
+func deleteRemovedPodDisruptionBudgets(cr *loggingv1beta1.Elasticsearch, appNames map[string]bool) error {
+	pdbs, err := k8sgo.ListPodDisruptionBudgets(cr.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, pdb := range pdbs {
+		if appNames[pdb.Name] || !isOwnedByElastic(cr, pdb.OwnerReferences) {
+			continue
+		}
+		err = k8sgo.DeletePodDisruptionBudget(cr.Namespace, pdb.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isOwnedByElastic is a method to check if an object carries the owner reference of the elasticsearch cluster
+func isOwnedByElastic(cr *loggingv1beta1.Elasticsearch, ownerReferences []metav1.OwnerReference) bool {
+	for _, ownerReference := range ownerReferences {
+		if ownerReference.UID == cr.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// isDisruptionBudgetRestricted is a method to check if the budget of a data node set is tightened until the cluster is green
+func isDisruptionBudgetRestricted(cr *loggingv1beta1.Elasticsearch, nodeSet loggingv1beta1.NodeSet) bool {
+	// the cluster state is empty until the cluster answered once, there are no shards to protect before
+	return isDataNodeSet(nodeSet) && cr.Status.ClusterState != "" && cr.Status.ClusterState != "green"
+}
+
+// getNodeSetDisruptionBudget is a method to get either the minimum available or the maximum unavailable pods of a node set
+// The node set gets no budget when both are nil
+func getNodeSetDisruptionBudget(cr *loggingv1beta1.Elasticsearch, nodeSet loggingv1beta1.NodeSet) (*intstr.IntOrString, *intstr.IntOrString, error) {
+	budget := nodeSet.PodDisruptionBudget
+	if budget != nil && budget.MinAvailable != nil && budget.MaxUnavailable != nil {
+		return nil, nil, fmt.Errorf("podDisruptionBudget of node set %s can only set one of minAvailable and maxUnavailable", nodeSet.Name)
+	}
+	// evicting a data node while shard copies are missing can make indices unavailable or lose data, no eviction is intended
+	if isDisruptionBudgetRestricted(cr, nodeSet) {
+		none := intstr.FromInt(0)
+		return nil, &none, nil
+	}
+	if budget != nil && (budget.MinAvailable != nil || budget.MaxUnavailable != nil) {
+		return budget.MinAvailable, budget.MaxUnavailable, nil
+	}
+	if hasNodeRole(nodeSet, "master") {
+		replicas := getNodeSetReplicas(cr, nodeSet.Name)
+		// a single master can not stay available during a drain, a budget for it would block every node drain
+		if replicas < 2 {
+			return nil, nil, nil
+		}
+		// every master node set keeps its own quorum, which keeps the quorum of all masters together
+		quorum := intstr.FromInt(int(replicas/2 + 1))
+		return &quorum, nil, nil
+	}
+	one := intstr.FromInt(1)
+	return nil, &one, nil
+}
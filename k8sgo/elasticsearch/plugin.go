@@ -0,0 +1,277 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+const (
+	pluginsHashAnnotation  = "logging.opstreelabs.in/plugins-hash"
+	pluginVolumeName       = "plugin-volume"
+	pluginSourceVolumeName = "plugin-source"
+	pluginSourcePath       = "/usr/share/elasticsearch/plugin-source"
+	pluginCachePath        = "/tmp/plugin-cache"
+	pluginCacheSubPath     = "plugin-cache"
+	defaultPluginMirror    = "https://artifacts.elastic.co/downloads/elasticsearch-plugins"
+	defaultPluginImagePath = "/plugins"
+)
+
+// pluginInstallScript installs the plugins from local archives, downloaded archives are cached on the data volume
+// so that restarted pods do not need to reach the plugin source again, they are only installed without checksum when they are unverified
+const pluginInstallScript = `set -e
+rm -rf /usr/share/elasticsearch/plugins/*
+mkdir -p ` + pluginCachePath + `
+used=""
+for source in $PLUGIN_SOURCES; do
+  case "$source" in
+    file://*)
+      archive="${source#file://}"
+      ;;
+    *)
+      archive="` + pluginCachePath + `/$(echo "$source" | sha256sum | cut -c1-16)-$(basename "$source")"
+      if [ ! -s "$archive" ]; then
+        echo "Downloading plugin $source"
+        curl -fsSL -o "$archive.tmp" "$source"
+        if curl -fsSL -o "$archive.sha512" "$source.sha512"; then
+          echo "$(cut -d' ' -f1 "$archive.sha512")  $archive.tmp" | sha512sum -c -
+        else
+          case " $UNVERIFIED_PLUGIN_SOURCES " in
+            *" $source "*) echo "Installing plugin $source without checksum" ;;
+            *) echo "Checksum $source.sha512 can not be downloaded"; rm -f "$archive.tmp"; exit 1 ;;
+          esac
+        fi
+        rm -f "$archive.sha512"
+        mv "$archive.tmp" "$archive"
+      fi
+      used="$used $archive"
+      ;;
+  esac
+  bin/elasticsearch-plugin install --batch "file://$archive"
+done
+for archive in ` + pluginCachePath + `/*; do
+  case "$used " in
+    *" $archive "*) ;;
+    *) rm -f "$archive" ;;
+  esac
+done`
+
+// GetElasticPluginStatus is a method to count the nodes which every installed plugin is installed on
+func GetElasticPluginStatus(cr *loggingv1beta1.Elasticsearch) ([]loggingv1beta1.PluginStatus, error) {
+	if cr.Spec.ESPlugins == nil {
+		return nil, nil
+	}
+	plugins, err := elasticgo.GetElasticPlugins(cr)
+	if err != nil {
+		return nil, err
+	}
+	pluginStatus := map[string]*loggingv1beta1.PluginStatus{}
+	for _, plugin := range plugins {
+		status, ok := pluginStatus[plugin.Component]
+		if !ok {
+			status = &loggingv1beta1.PluginStatus{Name: plugin.Component, Version: plugin.Version}
+			pluginStatus[plugin.Component] = status
+		}
+		status.Nodes++
+	}
+	var installedPlugins []loggingv1beta1.PluginStatus
+	for _, status := range pluginStatus {
+		installedPlugins = append(installedPlugins, *status)
+	}
+	sort.Slice(installedPlugins, func(i, j int) bool {
+		return installedPlugins[i].Name < installedPlugins[j].Name
+	})
+	return installedPlugins, nil
+}
+
+// getMissingPlugins is a method to list the plugins given by name which are not installed on every node
+func getMissingPlugins(cr *loggingv1beta1.Elasticsearch) []string {
+	var missingPlugins []string
+	if cr.Spec.ESPlugins == nil {
+		return nil
+	}
+	for _, plugin := range *cr.Spec.ESPlugins {
+		// the name of a plugin installed from an url is only known from its archive
+		if strings.Contains(plugin, "://") {
+			continue
+		}
+		installed := false
+		for _, status := range cr.Status.Plugins {
+			if status.Name == plugin && int(status.Nodes) >= len(cr.Status.Nodes) {
+				installed = true
+			}
+		}
+		if !installed {
+			missingPlugins = append(missingPlugins, plugin)
+		}
+	}
+	return missingPlugins
+}
+
+// getPluginSources is a method to resolve the plugins of the cluster into the urls of their archives
+func getPluginSources(cr *loggingv1beta1.Elasticsearch) []string {
+	var sources []string
+	if cr.Spec.ESPlugins == nil {
+		return nil
+	}
+	for _, plugin := range *cr.Spec.ESPlugins {
+		sources = append(sources, getPluginSource(cr, plugin))
+	}
+	return sources
+}
+
+// getUnverifiedPluginSources is a method to resolve the plugins which may be installed without checksum into the urls of their archives
+func getUnverifiedPluginSources(cr *loggingv1beta1.Elasticsearch) []string {
+	var sources []string
+	for _, plugin := range cr.Spec.UnverifiedPlugins {
+		sources = append(sources, getPluginSource(cr, plugin))
+	}
+	return sources
+}
+
+// getPluginSource is a method to resolve a plugin name or url into the url of its archive
+func getPluginSource(cr *loggingv1beta1.Elasticsearch, plugin string) string {
+	version := getElasticVersion(cr)
+	pluginSource := cr.Spec.PluginSource
+	switch {
+	case strings.Contains(plugin, "://"):
+		return plugin
+	case pluginSource != nil && (pluginSource.Volume != nil || pluginSource.Image != ""):
+		return fmt.Sprintf("file://%s/%s-%s.zip", pluginSourcePath, plugin, version)
+	case pluginSource != nil && pluginSource.MirrorURL != "":
+		return fmt.Sprintf("%s/%s/%s-%s.zip", strings.TrimSuffix(pluginSource.MirrorURL, "/"), plugin, plugin, version)
+	}
+	return fmt.Sprintf("%s/%s/%s-%s.zip", defaultPluginMirror, plugin, plugin, version)
+}
+
+// getPluginsHash is a method to generate the hash of the plugin archives which restarts the pods on changes
+func getPluginsHash(cr *loggingv1beta1.Elasticsearch) string {
+	hash := sha256.Sum256([]byte(strings.Join(getPluginSources(cr), "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// getPluginInitContainers is a method to create the init containers which install the plugins of a node set
+func getPluginInitContainers(cr *loggingv1beta1.Elasticsearch, role string) []corev1.Container {
+	if cr.Spec.ESPlugins == nil {
+		return nil
+	}
+	var initContainers []corev1.Container
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      pluginVolumeName,
+			MountPath: "/usr/share/elasticsearch/plugins",
+		},
+		{
+			Name:      fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role),
+			MountPath: pluginCachePath,
+			SubPath:   pluginCacheSubPath,
+		},
+	}
+	pluginSource := cr.Spec.PluginSource
+	if pluginSource != nil && pluginSource.Image != "" {
+		initContainers = append(initContainers, corev1.Container{
+			Name:            "plugin-source",
+			Image:           pluginSource.Image,
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(getNodeSetKubernetesConfig(cr, role)),
+			Command:         []string{"sh", "-c", fmt.Sprintf("cp -r %s/. %s/", getPluginSourcePath(pluginSource), pluginSourcePath)},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      pluginSourceVolumeName,
+					MountPath: pluginSourcePath,
+				},
+			},
+		})
+	}
+	if pluginSource != nil && (pluginSource.Volume != nil || pluginSource.Image != "") {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      pluginSourceVolumeName,
+			MountPath: pluginSourcePath,
+			SubPath:   getPluginSourceSubPath(pluginSource),
+			ReadOnly:  true,
+		})
+	}
+	initContainers = append(initContainers, corev1.Container{
+		Name:            "plugins",
+		Image:           getElasticImage(cr, role),
+		ImagePullPolicy: k8sgo.GetImagePullPolicy(getNodeSetKubernetesConfig(cr, role)),
+		Command:         []string{"bash", "-c", pluginInstallScript},
+		Env: []corev1.EnvVar{
+			{Name: "PLUGIN_SOURCES", Value: strings.Join(getPluginSources(cr), " ")},
+			{Name: "UNVERIFIED_PLUGIN_SOURCES", Value: strings.Join(getUnverifiedPluginSources(cr), " ")},
+		},
+		VolumeMounts: volumeMounts,
+	})
+	return initContainers
+}
+
+// getPluginVolumes is a method to create the volumes of the installed plugins and of the plugin archives
+func getPluginVolumes(cr *loggingv1beta1.Elasticsearch) []corev1.Volume {
+	if cr.Spec.ESPlugins == nil {
+		return nil
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: pluginVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	pluginSource := cr.Spec.PluginSource
+	switch {
+	case pluginSource != nil && pluginSource.Volume != nil:
+		volumes = append(volumes, corev1.Volume{
+			Name:         pluginSourceVolumeName,
+			VolumeSource: *pluginSource.Volume,
+		})
+	case pluginSource != nil && pluginSource.Image != "":
+		volumes = append(volumes, corev1.Volume{
+			Name: pluginSourceVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+	return volumes
+}
+
+// getPluginSourcePath is a method to get the directory of the plugin archives in the volume or image
+func getPluginSourcePath(pluginSource *loggingv1beta1.PluginSource) string {
+	if pluginSource.Path != "" {
+		return pluginSource.Path
+	}
+	return defaultPluginImagePath
+}
+
+// getPluginSourceSubPath is a method to get the directory of the plugin archives relative to the root of the volume
+func getPluginSourceSubPath(pluginSource *loggingv1beta1.PluginSource) string {
+	// archives copied out of an image are placed at the root of the volume
+	if pluginSource.Volume == nil {
+		return ""
+	}
+	return strings.TrimPrefix(path.Clean(getPluginSourcePath(pluginSource)), "/")
+}
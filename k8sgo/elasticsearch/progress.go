@@ -0,0 +1,88 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// GetElasticRollout is a method to describe the change which is being rolled out, an empty reason means the cluster is rolled out
+func GetElasticRollout(cr *loggingv1beta1.Elasticsearch) (string, string, error) {
+	if isUpgradeInProgress(cr) {
+		upgrade := cr.Status.Upgrade
+		var upgradedNodes int32
+		for _, node := range cr.Status.Nodes {
+			if node.Version == upgrade.TargetVersion {
+				upgradedNodes++
+			}
+		}
+		return "Upgrading", fmt.Sprintf("upgrading from %s to %s, %d of %d nodes run the new version", upgrade.FromVersion, upgrade.TargetVersion, upgradedNodes, upgrade.TotalNodes), nil
+	}
+	if scaleDown := cr.Status.ScaleDown; scaleDown != nil {
+		return "ScalingDown", fmt.Sprintf("removing %d nodes of %s, %d shards remaining", len(scaleDown.Nodes), scaleDown.Role, scaleDown.RemainingShards), nil
+	}
+	for _, expansion := range cr.Status.VolumeExpansion {
+		if expansion.Phase != volumeExpansionPhaseUnsupported {
+			return "ExpandingVolumes", fmt.Sprintf("volume %s is being resized to %s", expansion.Claim, expansion.RequestedSize), nil
+		}
+	}
+	if restore := cr.Status.Restore; restore != nil {
+		switch restore.Phase {
+		case restorePhasePending, restorePhaseStarting, restorePhaseRestoring:
+			return "Restoring", fmt.Sprintf("restoring snapshot %s, %d of %d shards recovered", restore.Snapshot, restore.RecoveredShards, restore.TotalShards), nil
+		}
+	}
+	for _, nodeSet := range GetNodeSets(cr) {
+		stateful, err := k8sgo.GetStateFulSet(cr.Namespace, fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, nodeSet.Name))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return "RollingOut", fmt.Sprintf("statefulset of node set %s is being created", nodeSet.Name), nil
+			}
+			return "", "", err
+		}
+		// statefulsets are only deleted with orphaned pods to be recreated with an expanded claim template
+		if stateful.DeletionTimestamp != nil {
+			return "ExpandingVolumes", fmt.Sprintf("statefulset of node set %s is recreated with the expanded claim template", nodeSet.Name), nil
+		}
+		if !k8sgo.IsStateFulSetRolledOut(stateful) {
+			return "RollingOut", fmt.Sprintf("node set %s has %d of %d nodes ready", nodeSet.Name, stateful.Status.ReadyReplicas, getNodeSetReplicas(cr, nodeSet.Name)), nil
+		}
+	}
+	if keystore := cr.Status.Keystore; keystore != nil && len(keystore.PendingPods) > 0 {
+		return "ReloadingSecureSettings", fmt.Sprintf("pods %s are rebuilding their keystore", strings.Join(keystore.PendingPods, ", ")), nil
+	}
+	return "", "", nil
+}
+
+// GetElasticDegradation is a method to describe why the cluster runs with reduced availability, an empty reason means it is healthy
+func GetElasticDegradation(cr *loggingv1beta1.Elasticsearch) (string, string) {
+	if restore := cr.Status.Restore; restore != nil && restore.Phase == restorePhaseFailed {
+		return "RestoreFailed", restore.Message
+	}
+	if missingPlugins := getMissingPlugins(cr); len(missingPlugins) > 0 {
+		return "PluginsMissing", fmt.Sprintf("plugins %s are not installed on every node", strings.Join(missingPlugins, ", "))
+	}
+	if cr.Status.ClusterState != "green" {
+		return "ClusterHealth", fmt.Sprintf("cluster health is %s", cr.Status.ClusterState)
+	}
+	return "", ""
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// Restore phases reported in the elasticsearch status
+const (
+	restorePhasePending   = "Pending"
+	restorePhaseStarting  = "Starting"
+	restorePhaseRestoring = "Restoring"
+	restorePhaseCompleted = "Completed"
+	restorePhaseFailed    = "Failed"
+	restorePhaseSkipped   = "Skipped"
+)
+
+// ReconcileElasticRestore is a method to restore the snapshot of restoreFrom exactly once into a new cluster
+func ReconcileElasticRestore(cr *loggingv1beta1.Elasticsearch, clusterInfo elasticgo.ESClusterDetails) error {
+	restoreFrom := cr.Spec.RestoreFrom
+	if restoreFrom == nil {
+		return nil
+	}
+	clusterState := clusterInfo.ClusterState
+	restore := cr.Status.Restore
+	if restore == nil && !isNewElasticCluster(cr, clusterInfo) {
+		cr.Status.Restore = &loggingv1beta1.RestoreStatus{
+			Repository: restoreFrom.Repository,
+			Snapshot:   restoreFrom.Snapshot,
+			Phase:      restorePhaseSkipped,
+			Message:    "restoreFrom was added to a cluster which was already running, snapshots are only restored into new clusters",
+		}
+		return nil
+	}
+	if restore == nil || (restore.Phase == restorePhasePending && (restore.Repository != restoreFrom.Repository || restore.Snapshot != restoreFrom.Snapshot)) {
+		restore = &loggingv1beta1.RestoreStatus{
+			Repository: restoreFrom.Repository,
+			Snapshot:   restoreFrom.Snapshot,
+			Phase:      restorePhasePending,
+		}
+		cr.Status.Restore = restore
+	}
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+
+	switch restore.Phase {
+	case restorePhasePending:
+		if clusterState != "green" && clusterState != "yellow" {
+			restore.Message = "waiting for cluster to become yellow or green"
+			return nil
+		}
+		repository, err := k8sgo.GetSnapshotRepository(cr.Namespace, restore.Repository)
+		if err != nil {
+			restore.Message = fmt.Sprintf("snapshot repository %s not found", restore.Repository)
+			return nil
+		}
+		err = ReconcileSnapshotRepository(cr, repository)
+		if err != nil {
+			restore.Message = err.Error()
+			return nil
+		}
+		// the marker is stored before the restore is started, so it is never started twice
+		restore.Phase = restorePhaseStarting
+		restore.Message = ""
+		restore.StartTime = &metav1.Time{Time: time.Now()}
+	case restorePhaseStarting:
+		progress, err := elasticgo.GetRestoreProgress(cr, restore.Repository, restore.Snapshot)
+		if err != nil {
+			return err
+		}
+		if progress.TotalShards == 0 {
+			logger.Info("Restoring snapshot", "repository", restore.Repository, "snapshot", restore.Snapshot)
+			err = elasticgo.RestoreSnapshot(cr, restore.Repository, restore.Snapshot, restoreFrom.Indices, restoreFrom.IncludeGlobalState)
+			if err != nil {
+				restore.Phase = restorePhaseFailed
+				restore.Message = err.Error()
+				return nil
+			}
+		}
+		restore.Phase = restorePhaseRestoring
+	case restorePhaseRestoring:
+		progress, err := elasticgo.GetRestoreProgress(cr, restore.Repository, restore.Snapshot)
+		if err != nil {
+			return err
+		}
+		restore.TotalShards = progress.TotalShards
+		restore.RecoveredShards = progress.RecoveredShards
+		restore.TotalBytes = progress.TotalBytes
+		restore.RecoveredBytes = progress.RecoveredBytes
+		// the recoveries of a restore which was just started may not be listed yet
+		inProgress, err := elasticgo.IsRestoreInProgress(cr, restore.Repository, restore.Snapshot)
+		if err != nil {
+			return err
+		}
+		if !inProgress && progress.RecoveredShards == progress.TotalShards {
+			logger.Info("Snapshot restore completed", "repository", restore.Repository, "snapshot", restore.Snapshot)
+			restore.Phase = restorePhaseCompleted
+			restore.CompletionTime = &metav1.Time{Time: time.Now()}
+		}
+	}
+	return nil
+}
+
+// isNewElasticCluster is a method to check that a cluster was never ready and holds no indices besides system indices
+func isNewElasticCluster(cr *loggingv1beta1.Elasticsearch, clusterInfo elasticgo.ESClusterDetails) bool {
+	if meta.IsStatusConditionTrue(cr.Status.Conditions, loggingv1beta1.ConditionReady) {
+		return false
+	}
+	for index := range clusterInfo.IndexHealth {
+		// backing indices of data streams hold user data although they are prefixed with a dot
+		if !strings.HasPrefix(index, ".") || strings.HasPrefix(index, ".ds-") {
+			return false
+		}
+	}
+	return true
+}
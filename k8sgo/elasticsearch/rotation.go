@@ -0,0 +1,213 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+const (
+	tlsHashAnnotation             = "logging.opstreelabs.in/tls-hash"
+	nextCACertName                = "next-ca.crt"
+	nextCAKeyName                 = "next-ca.key"
+	defaultCertificateRenewBefore = 30 * 24 * time.Hour
+)
+
+// Certificate rotation phases reported in the elasticsearch status
+const (
+	rotationPhaseValid              = "Valid"
+	rotationPhaseTrustingNewCA      = "TrustingNewCA"
+	rotationPhaseRemovingPreviousCA = "RemovingPreviousCA"
+)
+
+// ReconcileElasticTLSCertificates is a method to renew operator generated certificates before they expire
+func ReconcileElasticTLSCertificates(cr *loggingv1beta1.Elasticsearch) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Certificate")
+	caSecret, err := k8sgo.GetSecret(fmt.Sprintf("%s-tls-ca", cr.ObjectMeta.Name), cr.Namespace)
+	if err != nil {
+		return err
+	}
+	tlsSecret, err := k8sgo.GetSecret(fmt.Sprintf("%s-tls-cert", cr.ObjectMeta.Name), cr.Namespace)
+	if err != nil {
+		return err
+	}
+
+	switch getRotationPhase(caSecret, tlsSecret) {
+	case rotationPhaseTrustingNewCA:
+		// the new CA has to be part of the truststore of every node before any certificate is signed by it
+		if !bytes.Contains(tlsSecret.Data[certificateCAName], caSecret.Data[nextCACertName]) {
+			return updateElasticTrustBundle(cr, tlsSecret, caSecret.Data[certificateCAName], caSecret.Data[nextCACertName])
+		}
+		rolledOut, err := isElasticClusterRolledOut(cr)
+		if err != nil || !rolledOut {
+			return err
+		}
+		logger.Info("All nodes trust the new CA, issuing node certificates from it")
+		nodeCert, nodeKey, err := generateNodeCertificate(cr, caSecret.Data[nextCACertName], caSecret.Data[nextCAKeyName])
+		if err != nil {
+			return err
+		}
+		tlsSecret.Data[certificateCertName] = nodeCert
+		tlsSecret.Data[certificateKeyName] = nodeKey
+		err = k8sgo.UpdateSecret(cr.Namespace, tlsSecret)
+		if err != nil {
+			return err
+		}
+		caSecret.Data = map[string][]byte{
+			certificateCAName: caSecret.Data[nextCACertName],
+			certificateCAKey:  caSecret.Data[nextCAKeyName],
+		}
+		return k8sgo.UpdateSecret(cr.Namespace, caSecret)
+	case rotationPhaseRemovingPreviousCA:
+		rolledOut, err := isElasticClusterRolledOut(cr)
+		if err != nil || !rolledOut {
+			return err
+		}
+		logger.Info("All nodes use certificates of the new CA, removing previous CA from truststore")
+		return updateElasticTrustBundle(cr, tlsSecret, caSecret.Data[certificateCAName])
+	}
+
+	renewBefore := getCertificateRenewBefore(cr)
+	caCert, err := parseCertificate(caSecret.Data[certificateCAName])
+	if err != nil {
+		return err
+	}
+	if time.Until(caCert.NotAfter) < renewBefore {
+		logger.Info("CA certificate is about to expire, adding a new CA to the truststore", "notAfter", caCert.NotAfter)
+		newCACert, newCAKey, err := generateCACertificate(cr)
+		if err != nil {
+			return err
+		}
+		caSecret.Data[nextCACertName] = newCACert
+		caSecret.Data[nextCAKeyName] = newCAKey
+		err = k8sgo.UpdateSecret(cr.Namespace, caSecret)
+		if err != nil {
+			return err
+		}
+		return updateElasticTrustBundle(cr, tlsSecret, caSecret.Data[certificateCAName], newCACert)
+	}
+
+	nodeCert, err := parseCertificate(tlsSecret.Data[certificateCertName])
+	if err != nil {
+		return err
+	}
+	if time.Until(nodeCert.NotAfter) < renewBefore {
+		logger.Info("Node certificate is about to expire, renewing it", "notAfter", nodeCert.NotAfter)
+		return CreateElasticTLSSecret(cr)
+	}
+	if !reflect.DeepEqual(nodeCert.DNSNames, getNodeCertificateDNSNames(cr)) {
+		logger.Info("Node sets have changed, renewing node certificate")
+		return CreateElasticTLSSecret(cr)
+	}
+	return nil
+}
+
+// GetElasticTLSStatus is a method to get the expiry and rotation state of elasticsearch certificates
+func GetElasticTLSStatus(cr *loggingv1beta1.Elasticsearch) (*loggingv1beta1.TLSStatus, error) {
+	if IsIssuerEnabled(cr) {
+		return getIssuedTLSStatus(cr)
+	}
+	caSecret, err := k8sgo.GetSecret(fmt.Sprintf("%s-tls-ca", cr.ObjectMeta.Name), cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	tlsSecret, err := k8sgo.GetSecret(fmt.Sprintf("%s-tls-cert", cr.ObjectMeta.Name), cr.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := parseCertificate(caSecret.Data[certificateCAName])
+	if err != nil {
+		return nil, err
+	}
+	nodeCert, err := parseCertificate(tlsSecret.Data[certificateCertName])
+	if err != nil {
+		return nil, err
+	}
+	return &loggingv1beta1.TLSStatus{
+		NotAfter:      &metav1.Time{Time: nodeCert.NotAfter},
+		CANotAfter:    &metav1.Time{Time: caCert.NotAfter},
+		RotationPhase: getRotationPhase(caSecret, tlsSecret),
+	}, nil
+}
+
+// getRotationPhase is a method to derive the certificate rotation phase from the stored secrets
+func getRotationPhase(caSecret, tlsSecret *corev1.Secret) string {
+	if _, ok := caSecret.Data[nextCACertName]; ok {
+		return rotationPhaseTrustingNewCA
+	}
+	if countCertificates(tlsSecret.Data[certificateCAName]) > 1 {
+		return rotationPhaseRemovingPreviousCA
+	}
+	return rotationPhaseValid
+}
+
+// updateElasticTrustBundle is a method to replace the trusted CA certificates of the cluster
+func updateElasticTrustBundle(cr *loggingv1beta1.Elasticsearch, tlsSecret *corev1.Secret, caCerts ...[]byte) error {
+	trustBundle := bytes.Join(caCerts, nil)
+	tlsSecret.Data[certificateCAName] = trustBundle
+	err := k8sgo.UpdateSecret(cr.Namespace, tlsSecret)
+	if err != nil {
+		return err
+	}
+	return createElasticPublicCASecret(cr, trustBundle)
+}
+
+// isElasticClusterRolledOut is a method to check that every node runs with the current certificates
+func isElasticClusterRolledOut(cr *loggingv1beta1.Elasticsearch) (bool, error) {
+	tlsHash, err := k8sgo.GetSecretHash(fmt.Sprintf("%s-tls-cert", cr.ObjectMeta.Name), cr.Namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range getNodeSetNames(cr) {
+		stateful, err := k8sgo.GetStateFulSet(cr.Namespace, fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role))
+		if err != nil {
+			return false, err
+		}
+		if stateful.Spec.Template.Annotations[tlsHashAnnotation] != tlsHash || !k8sgo.IsStateFulSetRolledOut(stateful) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ValidateCertificateRenewBefore is a method to reject renewal windows which would renew certificates on every reconcile
+func ValidateCertificateRenewBefore(cr *loggingv1beta1.Elasticsearch) error {
+	if cr.Spec.Security == nil || cr.Spec.Security.CertificateRenewBefore == nil || IsIssuerEnabled(cr) {
+		return nil
+	}
+	renewBefore := cr.Spec.Security.CertificateRenewBefore.Duration
+	if renewBefore <= 0 || renewBefore >= nodeCertValidity/2 {
+		return fmt.Errorf("certificateRenewBefore %s has to be positive and shorter than half of the certificate validity %s", renewBefore, nodeCertValidity)
+	}
+	return nil
+}
+
+// getCertificateRenewBefore is a method to get the renewal window of certificates
+func getCertificateRenewBefore(cr *loggingv1beta1.Elasticsearch) time.Duration {
+	if cr.Spec.Security != nil && cr.Spec.Security.CertificateRenewBefore != nil {
+		return cr.Spec.Security.CertificateRenewBefore.Duration
+	}
+	return defaultCertificateRenewBefore
+}
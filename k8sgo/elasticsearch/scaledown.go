@@ -0,0 +1,190 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// Scale down phases reported in the elasticsearch status
+const (
+	scaleDownPhaseDraining  = "Draining"
+	scaleDownPhaseExcluding = "Excluding"
+	scaleDownPhaseDrained   = "Drained"
+)
+
+// ReconcileElasticScaleDown is a method to prepare elasticsearch nodes before they are removed
+func ReconcileElasticScaleDown(cr *loggingv1beta1.Elasticsearch) error {
+	if isUpgradeInProgress(cr) {
+		return nil
+	}
+	// node sets which were removed from the spec are scaled down to zero nodes
+	removedNodeSets, err := listRemovedNodeSets(cr)
+	if err != nil {
+		return err
+	}
+	nodeSets := getScaleDownNodeSets(cr, removedNodeSets)
+	// a running scale down is finished before the next role is looked at
+	if cr.Status.ScaleDown != nil {
+		done, err := reconcileRoleScaleDown(cr, getScaleDownNodeSet(nodeSets, cr.Status.ScaleDown.Role))
+		if err != nil || !done {
+			return err
+		}
+	}
+	for _, nodeSet := range nodeSets {
+		done, err := reconcileRoleScaleDown(cr, nodeSet)
+		if err != nil || !done {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileRoleScaleDown is a method to drain and exclude the nodes of a role which are removed
+func reconcileRoleScaleDown(cr *loggingv1beta1.Elasticsearch, nodeSet loggingv1beta1.NodeSet) (bool, error) {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	role := nodeSet.Name
+	appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role)
+	stateful, err := k8sgo.GetStateFulSet(cr.Namespace, appName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, clearScaleDown(cr, nodeSet)
+		}
+		return false, err
+	}
+	var currentReplicas int32 = 1
+	if stateful.Spec.Replicas != nil {
+		currentReplicas = *stateful.Spec.Replicas
+	}
+	nodes := getScaleDownNodes(cr, role, currentReplicas)
+	if len(nodes) == 0 {
+		return true, clearScaleDown(cr, nodeSet)
+	}
+
+	scaleDown := cr.Status.ScaleDown
+	if scaleDown == nil || scaleDown.Role != role || !reflect.DeepEqual(scaleDown.Nodes, nodes) {
+		scaleDown = &loggingv1beta1.ScaleDownStatus{Role: role, Nodes: nodes, Phase: scaleDownPhaseExcluding}
+		if isDataNodeSet(nodeSet) {
+			logger.Info("Draining shards from elasticsearch nodes before scale down", "nodes", nodes)
+			err = elasticgo.SetAllocationExclusion(cr, nodes)
+			if err != nil {
+				return false, err
+			}
+			scaleDown.Phase = scaleDownPhaseDraining
+		}
+		cr.Status.ScaleDown = scaleDown
+	}
+
+	switch scaleDown.Phase {
+	case scaleDownPhaseDraining:
+		remainingShards, err := elasticgo.CountNodeShards(cr, nodes)
+		if err != nil {
+			return false, err
+		}
+		scaleDown.RemainingShards = remainingShards
+		if remainingShards > 0 {
+			return false, nil
+		}
+		scaleDown.Phase = scaleDownPhaseExcluding
+		fallthrough
+	case scaleDownPhaseExcluding:
+		if hasNodeRole(nodeSet, "master") {
+			logger.Info("Removing elasticsearch masters from voting configuration", "nodes", nodes)
+			err = elasticgo.AddVotingConfigExclusions(cr, nodes)
+			if err != nil {
+				return false, err
+			}
+		}
+		// removed node sets are not rendered anymore, so their statefulset is scaled down here
+		if getNodeSet(cr, role) == nil {
+			err = k8sgo.ScaleStateFulSet(cr.Namespace, appName, 0)
+			if err != nil {
+				return false, err
+			}
+		}
+		logger.Info("Elasticsearch nodes are prepared, scaling down", "nodes", nodes)
+		scaleDown.Phase = scaleDownPhaseDrained
+	}
+	return false, nil
+}
+
+// getScaleDownNodes is a method to list the nodes above the desired replicas of a node set, removed node sets keep no nodes
+func getScaleDownNodes(cr *loggingv1beta1.Elasticsearch, role string, currentReplicas int32) []string {
+	var nodes []string
+	for ordinal := getNodeSetReplicas(cr, role); ordinal < currentReplicas; ordinal++ {
+		nodes = append(nodes, fmt.Sprintf("%s-%s-%d", cr.ObjectMeta.Name, role, ordinal))
+	}
+	return nodes
+}
+
+// getScaleDownNodeSets is a method to list the node sets of the spec and the removed node sets in scale down order, data nodes first and masters last
+func getScaleDownNodeSets(cr *loggingv1beta1.Elasticsearch, removedNodeSets []loggingv1beta1.NodeSet) []loggingv1beta1.NodeSet {
+	nodeSets := append(GetNodeSets(cr), removedNodeSets...)
+	sort.SliceStable(nodeSets, func(i, j int) bool {
+		return getUpgradeRank(nodeSets[i]) < getUpgradeRank(nodeSets[j])
+	})
+	return nodeSets
+}
+
+// getScaleDownNodeSet is a method to find the node set of a running scale down
+func getScaleDownNodeSet(nodeSets []loggingv1beta1.NodeSet, role string) loggingv1beta1.NodeSet {
+	for _, nodeSet := range nodeSets {
+		if nodeSet.Name == role {
+			return nodeSet
+		}
+	}
+	// the statefulset of a removed node set is gone, all exclusions it may have set are cleared
+	return loggingv1beta1.NodeSet{Name: role, Roles: []loggingv1beta1.NodeRole{"master", "data"}}
+}
+
+// clearScaleDown is a method to remove the exclusions once the scale down of a role is done or cancelled
+func clearScaleDown(cr *loggingv1beta1.Elasticsearch, nodeSet loggingv1beta1.NodeSet) error {
+	if cr.Status.ScaleDown == nil || cr.Status.ScaleDown.Role != nodeSet.Name {
+		return nil
+	}
+	if isDataNodeSet(nodeSet) {
+		err := elasticgo.SetAllocationExclusion(cr, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if hasNodeRole(nodeSet, "master") {
+		err := elasticgo.ClearVotingConfigExclusions(cr)
+		if err != nil {
+			return err
+		}
+	}
+	cr.Status.ScaleDown = nil
+	return nil
+}
+
+// getStatefulSetReplicas is a method to keep nodes running until they are prepared for removal
+func getStatefulSetReplicas(cr *loggingv1beta1.Elasticsearch, role string, replicas *int32) *int32 {
+	scaleDown := cr.Status.ScaleDown
+	if scaleDown != nil && scaleDown.Role == role && scaleDown.Phase != scaleDownPhaseDrained {
+		drainingReplicas := getNodeSetReplicas(cr, role) + int32(len(scaleDown.Nodes))
+		return &drainingReplicas
+	}
+	return replicas
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// newNodeSetStatefulSet is a method to generate the statefulset of a node set as it is stored by the operator
+func newNodeSetStatefulSet(cr *loggingv1beta1.Elasticsearch, name string, roles string, replicas int32) appsv1.StatefulSet {
+	return appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cr.ObjectMeta.Name + "-" + name,
+			Namespace:       cr.Namespace,
+			Annotations:     map[string]string{nodeRolesAnnotation: roles},
+			OwnerReferences: []metav1.OwnerReference{k8sgo.ElasticAsOwner(cr)},
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+}
+
+func TestRemovedDataNodeSetIsScaledDownToZero(t *testing.T) {
+	replicas := int32(2)
+	cr := &loggingv1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "logging", UID: "elasticsearch-uid"},
+		Spec: loggingv1beta1.ElasticsearchSpec{
+			ESMaster: &loggingv1beta1.NodeSpecificConfig{Replicas: &replicas},
+			NodeSets: []loggingv1beta1.NodeSet{
+				{Name: "hot", Roles: []loggingv1beta1.NodeRole{"data_hot"}},
+			},
+		},
+	}
+	other := &loggingv1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "logging", UID: "other-uid"}}
+	statefulSets := []appsv1.StatefulSet{
+		newNodeSetStatefulSet(cr, "master", "master", 2),
+		newNodeSetStatefulSet(cr, "hot", "data_hot", 2),
+		newNodeSetStatefulSet(cr, "warm", "data_warm", 3),
+		newNodeSetStatefulSet(other, "cold", "data_cold", 1),
+	}
+
+	removedNodeSets := getRemovedNodeSets(cr, statefulSets)
+	if len(removedNodeSets) != 1 || removedNodeSets[0].Name != "warm" {
+		t.Fatalf("expected only the warm node set to be removed, got %v", removedNodeSets)
+	}
+	if !isDataNodeSet(removedNodeSets[0]) {
+		t.Errorf("expected the removed warm node set to hold shards, got roles %v", removedNodeSets[0].Roles)
+	}
+
+	nodes := getScaleDownNodes(cr, "warm", 3)
+	expectedNodes := []string{"elasticsearch-warm-0", "elasticsearch-warm-1", "elasticsearch-warm-2"}
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("expected all nodes of the removed node set to be drained, got %v", nodes)
+	}
+	if nodes := getScaleDownNodes(cr, "hot", 1); len(nodes) != 0 {
+		t.Errorf("expected no nodes of the hot node set to be drained, got %v", nodes)
+	}
+
+	nodeSets := getScaleDownNodeSets(cr, removedNodeSets)
+	if nodeSets[len(nodeSets)-1].Name != "master" {
+		t.Errorf("expected masters to be scaled down last, got %v", nodeSets)
+	}
+	if nodeSet := getScaleDownNodeSet(nodeSets, "warm"); !isDataNodeSet(nodeSet) {
+		t.Errorf("expected the running scale down of the warm node set to drain shards, got roles %v", nodeSet.Roles)
+	}
+}
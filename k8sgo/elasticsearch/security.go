@@ -0,0 +1,209 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/thanhpk/randstr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// Elasticsearch role and user phases reported in their status
+const (
+	SecurityPhaseReady   = SnapshotPhaseReady
+	SecurityPhaseFailed  = SnapshotPhaseFailed
+	SecurityPhasePending = SnapshotPhasePending
+)
+
+// ReconcileElasticsearchRole is a method to create a role and revert changes which were made directly in elasticsearch
+func ReconcileElasticsearchRole(cr *loggingv1beta1.Elasticsearch, role *loggingv1beta1.ElasticsearchRole) error {
+	logger := k8sgo.LogGenerator(role.ObjectMeta.Name, role.Namespace, "ElasticsearchRole")
+	if cr.Spec.Security == nil {
+		return fmt.Errorf("security is not enabled on elasticsearch cluster %s", cr.ObjectMeta.Name)
+	}
+	desiredRole := generateRole(role.Spec.RoleDescriptor)
+	storedRole, err := elasticgo.GetRole(cr, role.ObjectMeta.Name)
+	if err != nil {
+		return err
+	}
+	if storedRole != nil && reflect.DeepEqual(*storedRole, desiredRole) {
+		return nil
+	}
+	if storedRole != nil {
+		logger.Info("Reverting role which differs from the resource")
+	}
+	return elasticgo.PutRole(cr, role.ObjectMeta.Name, desiredRole)
+}
+
+// ReconcileElasticsearchUser is a method to create a user with its password secret and revert changes which were made directly in elasticsearch
+func ReconcileElasticsearchUser(cr *loggingv1beta1.Elasticsearch, user *loggingv1beta1.ElasticsearchUser) error {
+	logger := k8sgo.LogGenerator(user.ObjectMeta.Name, user.Namespace, "ElasticsearchUser")
+	if cr.Spec.Security == nil {
+		return fmt.Errorf("security is not enabled on elasticsearch cluster %s", cr.ObjectMeta.Name)
+	}
+	username := GetElasticsearchUsername(user)
+	password, err := getUserPassword(user, username)
+	if err != nil {
+		return err
+	}
+	// a renamed user is removed, otherwise it would keep its access
+	if user.Status.Username != "" && user.Status.Username != username {
+		err = elasticgo.DeleteUser(cr, user.Status.Username)
+		if err != nil {
+			return err
+		}
+	}
+	user.Status.Username = username
+
+	desiredUser := generateUser(user)
+	storedUser, err := elasticgo.GetUser(cr, username)
+	if err != nil {
+		return err
+	}
+	if storedUser == nil {
+		logger.Info("Creating user", "username", username)
+		desiredUser.Password = password
+		return elasticgo.PutUser(cr, username, desiredUser)
+	}
+	if !reflect.DeepEqual(*storedUser, desiredUser) {
+		logger.Info("Reverting user which differs from the resource", "username", username)
+		err = elasticgo.PutUser(cr, username, desiredUser)
+		if err != nil {
+			return err
+		}
+	}
+	// disabled users can not authenticate, their password is checked once they are enabled again
+	if !desiredUser.Enabled {
+		return nil
+	}
+	authenticated, err := elasticgo.AuthenticateUser(cr, username, password)
+	if err != nil || authenticated {
+		return err
+	}
+	logger.Info("Resetting password which was changed in elasticsearch", "username", username)
+	desiredUser.Password = password
+	return elasticgo.PutUser(cr, username, desiredUser)
+}
+
+// GetElasticsearchUsername is a method to get the name of a user in elasticsearch
+func GetElasticsearchUsername(user *loggingv1beta1.ElasticsearchUser) string {
+	if user.Spec.Username != "" {
+		return user.Spec.Username
+	}
+	return user.ObjectMeta.Name
+}
+
+// getUserPassword is a method to read the password of a user, a password is generated when no secret is referenced
+func getUserPassword(user *loggingv1beta1.ElasticsearchUser, username string) (string, error) {
+	if user.Spec.PasswordSecret != nil {
+		secret, err := k8sgo.GetSecret(user.Spec.PasswordSecret.Name, user.Namespace)
+		if err != nil {
+			return "", err
+		}
+		password, ok := secret.Data[user.Spec.PasswordSecret.Key]
+		if !ok || len(password) == 0 {
+			return "", fmt.Errorf("secret %s has no %s key", user.Spec.PasswordSecret.Name, user.Spec.PasswordSecret.Key)
+		}
+		user.Status.SecretName = user.Spec.PasswordSecret.Name
+		return string(password), nil
+	}
+
+	secretName := fmt.Sprintf("%s-user-credentials", user.ObjectMeta.Name)
+	password := randstr.String(16)
+	secret, err := k8sgo.GetSecret(secretName, user.Namespace)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	// secrets of other objects are never taken over, their data could be replaced by the credentials of the user
+	if err == nil && !k8sgo.IsSecretOwnedBy(secret, user.UID) {
+		return "", &SecretConflictError{SecretName: secretName}
+	}
+	if err == nil && len(secret.Data["password"]) > 0 {
+		password = string(secret.Data["password"])
+		if string(secret.Data["username"]) == username {
+			user.Status.SecretName = secretName
+			return password, nil
+		}
+	}
+	labels := map[string]string{
+		"app": user.ObjectMeta.Name,
+	}
+	secretParams := k8sgo.SecretsParameters{
+		Name:        secretName,
+		OwnerDef:    k8sgo.ElasticsearchUserAsOwner(user),
+		Namespace:   user.Namespace,
+		SecretsMeta: k8sgo.GenerateObjectMetaInformation(secretName, user.Namespace, labels, k8sgo.GenerateAnnotations()),
+		SecretData: map[string][]byte{
+			"username": []byte(username),
+			"password": []byte(password),
+		},
+	}
+	err = k8sgo.CreateOrUpdateSecret(user.Namespace, k8sgo.GenerateSecret(secretParams))
+	if err != nil {
+		return "", err
+	}
+	user.Status.SecretName = secretName
+	return password, nil
+}
+
+// generateRole is a method to generate the role as elasticsearch returns it, so that stored roles can be compared
+func generateRole(role loggingv1beta1.RoleDescriptor) elasticgo.ESRole {
+	esRole := elasticgo.ESRole{
+		Cluster:      append([]string{}, role.Cluster...),
+		Indices:      []elasticgo.ESIndexPrivileges{},
+		Applications: []elasticgo.ESApplicationPrivileges{},
+		RunAs:        append([]string{}, role.RunAs...),
+	}
+	for _, index := range role.Indices {
+		indexPrivileges := elasticgo.ESIndexPrivileges{
+			Names:                  index.Names,
+			Privileges:             index.Privileges,
+			Query:                  index.Query,
+			AllowRestrictedIndices: index.AllowRestrictedIndices,
+		}
+		if index.FieldSecurity != nil {
+			indexPrivileges.FieldSecurity = &elasticgo.ESFieldSecurity{
+				Grant:  index.FieldSecurity.Grant,
+				Except: index.FieldSecurity.Except,
+			}
+		}
+		esRole.Indices = append(esRole.Indices, indexPrivileges)
+	}
+	for _, application := range role.Applications {
+		esRole.Applications = append(esRole.Applications, elasticgo.ESApplicationPrivileges{
+			Application: application.Application,
+			Privileges:  application.Privileges,
+			Resources:   application.Resources,
+		})
+	}
+	return esRole
+}
+
+// generateUser is a method to generate the user as elasticsearch returns it, so that stored users can be compared
+func generateUser(user *loggingv1beta1.ElasticsearchUser) elasticgo.ESUser {
+	return elasticgo.ESUser{
+		Roles:    append([]string{}, user.Spec.Roles...),
+		FullName: user.Spec.FullName,
+		Email:    user.Spec.Email,
+		Enabled:  user.Spec.Enabled == nil || *user.Spec.Enabled,
+	}
+}
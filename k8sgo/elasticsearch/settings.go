@@ -0,0 +1,103 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// operatorClusterSettings are changed by the operator during upgrades and scale downs and can not be declared
+var operatorClusterSettings = map[string]bool{
+	"cluster.routing.allocation.enable":        true,
+	"cluster.routing.allocation.exclude._name": true,
+}
+
+// ReconcileElasticClusterSettings is a method to apply the declared persistent cluster settings and reset the removed ones
+func ReconcileElasticClusterSettings(cr *loggingv1beta1.Elasticsearch) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	for key := range cr.Spec.ClusterSettings {
+		if operatorClusterSettings[key] {
+			return fmt.Errorf("cluster setting %s is managed by the operator", key)
+		}
+	}
+	storedSettings, err := elasticgo.GetPersistentClusterSettings(cr)
+	if err != nil {
+		return err
+	}
+
+	changedSettings := map[string]interface{}{}
+	for key, value := range cr.Spec.ClusterSettings {
+		storedValue, ok := storedSettings[key]
+		if !ok || getClusterSettingValue(storedValue) != value {
+			changedSettings[key] = value
+		}
+	}
+	// settings are only reset when they were set by the operator, settings made by hand are kept
+	for _, key := range cr.Status.AppliedClusterSettings {
+		if _, ok := cr.Spec.ClusterSettings[key]; ok {
+			continue
+		}
+		if _, ok := storedSettings[key]; ok {
+			changedSettings[key] = nil
+		}
+	}
+	if len(changedSettings) > 0 {
+		logger.Info("Updating persistent cluster settings", "settings", getSortedKeys(changedSettings))
+		err = elasticgo.PutPersistentClusterSettings(cr, changedSettings)
+		if err != nil {
+			return err
+		}
+	}
+
+	var appliedSettings []string
+	for key := range cr.Spec.ClusterSettings {
+		appliedSettings = append(appliedSettings, key)
+	}
+	sort.Strings(appliedSettings)
+	cr.Status.AppliedClusterSettings = appliedSettings
+	return nil
+}
+
+// getClusterSettingValue is a method to convert a flat setting into the string representation of the spec
+func getClusterSettingValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		var values []string
+		for _, item := range typedValue {
+			values = append(values, fmt.Sprint(item))
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
+
+// getSortedKeys is a method to list the keys of settings in a stable order for logging
+func getSortedKeys(settings map[string]interface{}) []string {
+	var keys []string
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
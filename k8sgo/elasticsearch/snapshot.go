@@ -0,0 +1,222 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// Snapshot repository and policy phases reported in their status
+const (
+	SnapshotPhaseReady   = "Ready"
+	SnapshotPhaseFailed  = "Failed"
+	SnapshotPhasePending = "Pending"
+)
+
+// CreateSnapshotCredentialsSecret is a method to collect the credentials of all snapshot repositories into a keystore secret
+func CreateSnapshotCredentialsSecret(cr *loggingv1beta1.Elasticsearch) error {
+	secretName := getSnapshotCredentialsSecretName(cr)
+	secretData := map[string][]byte{}
+	repositories, err := getSnapshotRepositories(cr)
+	if err != nil {
+		return err
+	}
+	for _, repository := range repositories {
+		if repository.Spec.CredentialsSecret == nil {
+			continue
+		}
+		credentials, err := k8sgo.GetSecret(*repository.Spec.CredentialsSecret, cr.Namespace)
+		if err != nil {
+			return err
+		}
+		for key, value := range credentials.Data {
+			secretData[fmt.Sprintf("%s.client.%s.%s", repository.Spec.Type, getSnapshotClient(&repository), key)] = value
+		}
+	}
+	_, err = k8sgo.GetSecret(secretName, cr.Namespace)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		// clusters without repository credentials keep their keystore untouched
+		if len(secretData) == 0 {
+			return nil
+		}
+	}
+	labels := map[string]string{
+		"app": cr.ObjectMeta.Name,
+	}
+	secretParams := k8sgo.SecretsParameters{
+		Name:        secretName,
+		OwnerDef:    k8sgo.ElasticAsOwner(cr),
+		Namespace:   cr.Namespace,
+		SecretsMeta: k8sgo.GenerateObjectMetaInformation(secretName, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
+		SecretData:  secretData,
+	}
+	return k8sgo.CreateOrUpdateSecret(cr.Namespace, k8sgo.GenerateSecret(secretParams))
+}
+
+// ReconcileSnapshotRepository is a method to register a snapshot repository when it is missing or has changed
+func ReconcileSnapshotRepository(cr *loggingv1beta1.Elasticsearch, repository *loggingv1beta1.SnapshotRepository) error {
+	logger := k8sgo.LogGenerator(repository.ObjectMeta.Name, repository.Namespace, "SnapshotRepository")
+	desiredRepository := generateSnapshotRepository(repository)
+	storedRepository, err := elasticgo.GetSnapshotRepository(cr, repository.ObjectMeta.Name)
+	if err != nil {
+		return err
+	}
+	if storedRepository != nil && reflect.DeepEqual(*storedRepository, desiredRepository) {
+		return nil
+	}
+	logger.Info("Registering snapshot repository", "type", desiredRepository.Type)
+	err = elasticgo.PutSnapshotRepository(cr, repository.ObjectMeta.Name, desiredRepository)
+	if err != nil {
+		return err
+	}
+	repository.Status.LastVerifyTime = &metav1.Time{Time: time.Now()}
+	return nil
+}
+
+// ReconcileSnapshotPolicy is a method to update a snapshot lifecycle policy and report its last executions
+func ReconcileSnapshotPolicy(cr *loggingv1beta1.Elasticsearch, policy *loggingv1beta1.SnapshotPolicy) error {
+	logger := k8sgo.LogGenerator(policy.ObjectMeta.Name, policy.Namespace, "SnapshotPolicy")
+	desiredPolicy := generateSnapshotPolicy(policy)
+	storedPolicy, err := elasticgo.GetSnapshotPolicy(cr, policy.ObjectMeta.Name)
+	if err != nil {
+		return err
+	}
+	if storedPolicy == nil || !reflect.DeepEqual(storedPolicy.Policy, desiredPolicy) {
+		logger.Info("Updating snapshot lifecycle policy", "schedule", desiredPolicy.Schedule)
+		err = elasticgo.PutSnapshotPolicy(cr, policy.ObjectMeta.Name, desiredPolicy)
+		if err != nil {
+			return err
+		}
+		storedPolicy, err = elasticgo.GetSnapshotPolicy(cr, policy.ObjectMeta.Name)
+		if err != nil || storedPolicy == nil {
+			return err
+		}
+	}
+	if storedPolicy.LastSuccess != nil {
+		policy.Status.LastSuccess = &metav1.Time{Time: time.UnixMilli(storedPolicy.LastSuccess.Time)}
+		policy.Status.LastSuccessName = storedPolicy.LastSuccess.SnapshotName
+	}
+	if storedPolicy.LastFailure != nil {
+		policy.Status.LastFailure = &metav1.Time{Time: time.UnixMilli(storedPolicy.LastFailure.Time)}
+		policy.Status.LastFailureName = storedPolicy.LastFailure.SnapshotName
+		policy.Status.LastFailureDetails = storedPolicy.LastFailure.Details
+	}
+	if storedPolicy.NextExecutionMillis > 0 {
+		policy.Status.NextExecution = &metav1.Time{Time: time.UnixMilli(storedPolicy.NextExecutionMillis)}
+	}
+	policy.Status.SnapshotsTaken = storedPolicy.Stats.SnapshotsTaken
+	policy.Status.SnapshotsFailed = storedPolicy.Stats.SnapshotsFailed
+	return nil
+}
+
+// getSnapshotClientEnvVariables is a method to generate the client settings of all snapshot repositories
+func getSnapshotClientEnvVariables(cr *loggingv1beta1.Elasticsearch) ([]corev1.EnvVar, error) {
+	var envVars []corev1.EnvVar
+	repositories, err := getSnapshotRepositories(cr)
+	if err != nil {
+		return nil, err
+	}
+	for _, repository := range repositories {
+		for key, value := range repository.Spec.ClientSettings {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  fmt.Sprintf("%s.client.%s.%s", repository.Spec.Type, getSnapshotClient(&repository), key),
+				Value: value,
+			})
+		}
+	}
+	return envVars, nil
+}
+
+// getSnapshotRepositories is a method to list the snapshot repositories of a cluster which are not being deleted
+func getSnapshotRepositories(cr *loggingv1beta1.Elasticsearch) ([]loggingv1beta1.SnapshotRepository, error) {
+	var clusterRepositories []loggingv1beta1.SnapshotRepository
+	repositories, err := k8sgo.ListSnapshotRepositories(cr.Namespace)
+	if err != nil {
+		// clusters keep working when the snapshot custom resource definitions are not installed
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, repository := range repositories {
+		if repository.Spec.ElasticsearchRef.Name == cr.ObjectMeta.Name && repository.DeletionTimestamp == nil {
+			clusterRepositories = append(clusterRepositories, repository)
+		}
+	}
+	return clusterRepositories, nil
+}
+
+// generateSnapshotRepository is a method to generate the elasticsearch definition of a snapshot repository
+func generateSnapshotRepository(repository *loggingv1beta1.SnapshotRepository) elasticgo.ESSnapshotRepository {
+	settings := map[string]string{}
+	for key, value := range repository.Spec.Settings {
+		settings[key] = value
+	}
+	if repository.Spec.Type != "fs" {
+		settings["client"] = getSnapshotClient(repository)
+	}
+	return elasticgo.ESSnapshotRepository{Type: repository.Spec.Type, Settings: settings}
+}
+
+// generateSnapshotPolicy is a method to generate the elasticsearch definition of a snapshot lifecycle policy
+func generateSnapshotPolicy(policy *loggingv1beta1.SnapshotPolicy) elasticgo.ESSnapshotPolicy {
+	snapshotPolicy := elasticgo.ESSnapshotPolicy{
+		Name:       policy.Spec.SnapshotName,
+		Schedule:   policy.Spec.Schedule,
+		Repository: policy.Spec.Repository,
+	}
+	if len(policy.Spec.Indices) > 0 || policy.Spec.IgnoreUnavailable != nil || policy.Spec.IncludeGlobalState != nil {
+		snapshotPolicy.Config = &elasticgo.ESSnapshotPolicyConfig{
+			Indices:            policy.Spec.Indices,
+			IgnoreUnavailable:  policy.Spec.IgnoreUnavailable,
+			IncludeGlobalState: policy.Spec.IncludeGlobalState,
+		}
+	}
+	if policy.Spec.Retention != nil {
+		snapshotPolicy.Retention = &elasticgo.ESSnapshotPolicyRetention{
+			ExpireAfter: policy.Spec.Retention.ExpireAfter,
+			MinCount:    policy.Spec.Retention.MinCount,
+			MaxCount:    policy.Spec.Retention.MaxCount,
+		}
+	}
+	return snapshotPolicy
+}
+
+// getSnapshotClient is a method to get the client name which holds the credentials of a repository
+func getSnapshotClient(repository *loggingv1beta1.SnapshotRepository) string {
+	if repository.Spec.Client != "" {
+		return repository.Spec.Client
+	}
+	return "default"
+}
+
+// getSnapshotCredentialsSecretName is a method to get the name of the keystore secret of repository credentials
+func getSnapshotCredentialsSecretName(cr *loggingv1beta1.Elasticsearch) string {
+	return fmt.Sprintf("%s-snapshot-credentials", cr.ObjectMeta.Name)
+}
@@ -25,22 +25,25 @@ import (
 )
 
 // CreateElasticsearchStatefulSet is a method to create elasticsearch statefulset
-func CreateElasticsearchStatefulSet(cr *loggingv1beta1.Elasticsearch, nodeConfig *loggingv1beta1.NodeSpecificConfig, role string, envVars []corev1.EnvVar) error {
+func CreateElasticsearchStatefulSet(cr *loggingv1beta1.Elasticsearch, nodeConfig *loggingv1beta1.NodeSpecificConfig, role string, envVars []corev1.EnvVar, configHash string) error {
 	appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role)
-	labels := map[string]string{
-		"app":  appName,
-		"role": role,
+	labels := getNodeSetLabels(cr, role)
+	statefulsetAnnotations := k8sgo.GenerateAnnotations()
+	// the roles are kept on the statefulset, so that the node set can still be drained after it was removed from the spec
+	if nodeSet := getNodeSet(cr, role); nodeSet != nil {
+		statefulsetAnnotations[nodeRolesAnnotation] = getNodeRoles(*nodeSet)
 	}
 	statefulsetParams := k8sgo.StatefulSetParameters{
 		OwnerDef:        k8sgo.ElasticAsOwner(cr),
-		StatefulSetMeta: k8sgo.GenerateObjectMetaInformation(appName, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
+		StatefulSetMeta: k8sgo.GenerateObjectMetaInformation(appName, cr.Namespace, labels, statefulsetAnnotations),
 		Namespace:       cr.Namespace,
 		ContainerParams: k8sgo.ContainerParams{
-			Name:           "elastic",
-			Image:          fmt.Sprintf("docker.elastic.co/elasticsearch/elasticsearch:%s", cr.Spec.ESVersion),
-			VolumeMount:    getVolumeMounts(cr, role),
-			EnvVar:         envVars,
-			ReadinessProbe: createProbeInfo(),
+			Name:            "elastic",
+			Image:           getElasticImage(cr, role),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(nodeConfig.KubernetesConfig),
+			VolumeMount:     getVolumeMounts(cr, role),
+			EnvVar:          envVars,
+			ReadinessProbe:  createProbeInfo(),
 		},
 		Labels:      labels,
 		Annotations: k8sgo.GenerateAnnotations(),
@@ -55,15 +58,27 @@ func CreateElasticsearchStatefulSet(cr *loggingv1beta1.Elasticsearch, nodeConfig
 		},
 	}
 	if nodeConfig.Replicas != nil {
-		statefulsetParams.Replicas = nodeConfig.Replicas
-	}
-	if cr.Spec.ESPlugins != nil {
-		statefulsetParams.ESPlugins = cr.Spec.ESPlugins
-	}
-	if cr.Spec.ESKeystoreSecret != nil {
-		statefulsetParams.ESKeystoreSecret = cr.Spec.ESKeystoreSecret
+		statefulsetParams.Replicas = getStatefulSetReplicas(cr, role, nodeConfig.Replicas)
+	}
+	statefulsetParams.Partition = getUpgradePartition(cr, role, getNodeSetReplicas(cr, role))
+	// the user security context applies to every container except the privileged sysctl init container
+	containerSecurityContext := getContainerSecurityContext(cr, role)
+	statefulsetParams.ContainerParams.SecurityContext = containerSecurityContext
+	statefulsetParams.SecurityContext = getPodSecurityContext(cr, role)
+	statefulsetParams.SysctlInitContainer = !isUnprivilegedMode(cr)
+	statefulsetParams.PluginInitContainers = withContainerSecurityContext(getPluginInitContainers(cr, role), containerSecurityContext)
+	statefulsetParams.KeystoreInitContainers = withContainerSecurityContext(getKeystoreInitContainers(cr, role), containerSecurityContext)
+	statefulsetParams.SidecarContainers = withContainerSecurityContext(getKeystoreSidecarContainers(cr, role), containerSecurityContext)
+	statefulsetParams.ExtraVolumes = getVolumes(cr, role)
+	podAnnotations, err := getPodAnnotations(cr)
+	if err != nil {
+		return err
 	}
-	statefulsetParams.ExtraVolumes = getVolumes(cr)
+	statefulsetParams.PodAnnotations = podAnnotations
+	statefulsetParams.PodAnnotations[configHashAnnotation] = configHash
+	statefulsetParams.InitContainers = withContainerSecurityContext(append(getSysctlWaitInitContainers(cr, role), getZoneInitContainers(cr, role)...), containerSecurityContext)
+	statefulsetParams.ImagePullSecrets = k8sgo.GetImagePullSecrets(nodeConfig.KubernetesConfig)
+	statefulsetParams.TopologySpreadConstraints = getTopologySpreadConstraints(cr, labels)
 
 	if nodeConfig != nil {
 		if nodeConfig.CustomConfig != nil {
@@ -92,13 +107,37 @@ func CreateElasticsearchStatefulSet(cr *loggingv1beta1.Elasticsearch, nodeConfig
 			statefulsetParams.ContainerParams.InitResources = &corev1.ResourceRequirements{}
 		}
 	}
-	err := k8sgo.CreateOrUpdateStateFul(statefulsetParams)
+	err = k8sgo.CreateOrUpdateStateFul(statefulsetParams)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// getPodAnnotations is a method to generate annotations which restart pods on changes
+func getPodAnnotations(cr *loggingv1beta1.Elasticsearch) (map[string]string, error) {
+	annotations := map[string]string{}
+	// cert-manager and users renew their certificates in place and elasticsearch reloads them without restart
+	if IsGeneratedCertificateRequired(cr) {
+		tlsHash, err := k8sgo.GetSecretHash(fmt.Sprintf("%s-tls-cert", cr.ObjectMeta.Name), cr.Namespace)
+		if err == nil {
+			annotations[tlsHashAnnotation] = tlsHash
+		}
+	}
+	// reloadable secure settings like repository credentials are rebuilt in the running pods by the keystore reloader
+	if isKeystoreEnabled(cr) {
+		_, restartHash, err := getKeystoreHashes(cr)
+		if err != nil {
+			return nil, err
+		}
+		annotations[keystoreRestartHashAnnotation] = restartHash
+	}
+	if cr.Spec.ESPlugins != nil {
+		annotations[pluginsHashAnnotation] = getPluginsHash(cr)
+	}
+	return annotations, nil
+}
+
 // getVolumeMounts is a method to get volume mounts for statefulset
 func getVolumeMounts(cr *loggingv1beta1.Elasticsearch, role string) *[]corev1.VolumeMount {
 	appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role)
@@ -108,23 +147,18 @@ func getVolumeMounts(cr *loggingv1beta1.Elasticsearch, role string) *[]corev1.Vo
 			MountPath: "/usr/share/elasticsearch/data",
 		},
 	}
-	if cr.Spec.Security != nil {
-		if cr.Spec.Security.TLSEnabled != nil && *cr.Spec.Security.TLSEnabled {
-			volumeMounts = append(volumeMounts, corev1.VolumeMount{
-				Name:      "tls-cert",
-				MountPath: "/usr/share/elasticsearch/config/certs",
-			})
-		}
-	}
+	_, configVolumeMount := getConfigVolume(cr, role)
+	volumeMounts = append(volumeMounts, configVolumeMount)
+	volumeMounts = append(volumeMounts, getTLSVolumeMounts(cr)...)
 	if cr.Spec.ESPlugins != nil {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      "plugin-volume",
+			Name:      pluginVolumeName,
 			MountPath: "/usr/share/elasticsearch/plugins",
 		})
 	}
-	if cr.Spec.ESKeystoreSecret != nil {
+	if isKeystoreEnabled(cr) {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      "keystore-volume",
+			Name:      keystoreVolumeName,
 			MountPath: "/usr/share/elasticsearch/config/elasticsearch.keystore",
 			SubPath:   "elasticsearch.keystore",
 		})
@@ -133,46 +167,13 @@ func getVolumeMounts(cr *loggingv1beta1.Elasticsearch, role string) *[]corev1.Vo
 }
 
 // getVolumes is a method to define addtional volumes
-func getVolumes(cr *loggingv1beta1.Elasticsearch) *[]corev1.Volume {
-	var volume []corev1.Volume
-	if cr.Spec.Security != nil {
-		if cr.Spec.Security.TLSEnabled != nil && *cr.Spec.Security.TLSEnabled {
-			volume = append(volume, corev1.Volume{
-				Name: "tls-cert",
-				VolumeSource: corev1.VolumeSource{
-					Secret: &corev1.SecretVolumeSource{
-						SecretName: fmt.Sprintf("%s-tls-cert", cr.ObjectMeta.Name),
-					},
-				},
-			})
-		}
-	}
-	if cr.Spec.ESPlugins != nil {
-		volume = append(volume, corev1.Volume{
-			Name: "plugin-volume",
-			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
-			},
-		})
-	}
-	if cr.Spec.ESKeystoreSecret != nil {
-		volume = append(volume, corev1.Volume{
-			Name: "keystore-volume",
-			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
-			},
-		})
-	}
-	if cr.Spec.ESKeystoreSecret != nil {
-		volume = append(volume, corev1.Volume{
-			Name: "keystore-secret",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: *cr.Spec.ESKeystoreSecret,
-				},
-			},
-		})
-	}
+func getVolumes(cr *loggingv1beta1.Elasticsearch, role string) *[]corev1.Volume {
+	configVolume, _ := getConfigVolume(cr, role)
+	volume := []corev1.Volume{configVolume}
+	volume = append(volume, getTLSVolumes(cr)...)
+	volume = append(volume, getZoneVolumes(cr)...)
+	volume = append(volume, getPluginVolumes(cr)...)
+	volume = append(volume, getKeystoreVolumes(cr)...)
 	return &volume
 }
 
@@ -207,7 +208,7 @@ func generateEnvVariables(cr *loggingv1beta1.Elasticsearch, nodeConfig loggingv1
 				},
 			})
 		}
-		if cr.Spec.Security.TLSEnabled != nil && *cr.Spec.Security.TLSEnabled {
+		if cr.Spec.Security.IsHTTPTLSEnabled() {
 			envVars = append(envVars, corev1.EnvVar{Name: "SCHEME", Value: "https"})
 		} else {
 			envVars = append(envVars, corev1.EnvVar{Name: "SCHEME", Value: "http"})
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"strings"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+)
+
+// SetElasticClusterStatus is a method to publish the observed shards and nodes of the cluster in the status
+func SetElasticClusterStatus(cr *loggingv1beta1.Elasticsearch, clusterInfo elasticgo.ESClusterDetails) {
+	indices := int32(len(clusterInfo.IndexHealth))
+	cr.Status.ClusterState = clusterInfo.ClusterState
+	cr.Status.ActiveShards = &clusterInfo.Shards
+	cr.Status.Indices = &indices
+	cr.Status.Shards = &loggingv1beta1.ShardStatus{
+		Active:       clusterInfo.Shards,
+		Primary:      clusterInfo.PrimaryShards,
+		Relocating:   clusterInfo.RelocatingShards,
+		Initializing: clusterInfo.InitializingShards,
+		Unassigned:   clusterInfo.UnassignedShards,
+	}
+
+	var nodes []loggingv1beta1.NodeStatus
+	nodesPerRole := map[string]int32{}
+	nodesPerNodeSet := map[string]int32{}
+	for _, node := range clusterInfo.Nodes {
+		nodes = append(nodes, loggingv1beta1.NodeStatus{
+			Name:            node.Name,
+			Roles:           node.Roles,
+			Master:          node.Master,
+			Version:         node.Version,
+			HeapUsedPercent: node.HeapUsedPercent,
+			HeapMaxBytes:    node.HeapMaxBytes,
+			DiskUsedPercent: node.DiskUsedPercent,
+			DiskUsedBytes:   node.DiskUsedBytes,
+			DiskTotalBytes:  node.DiskTotalBytes,
+			Shards:          node.Shards,
+		})
+		for _, role := range node.Roles {
+			nodesPerRole[role]++
+		}
+		if len(node.Roles) == 0 {
+			nodesPerRole["coordinating_only"]++
+		}
+		nodesPerNodeSet[getNodeSetOfNode(cr, node.Name)]++
+	}
+	cr.Status.Nodes = nodes
+	cr.Status.NodesPerRole = nodesPerRole
+
+	// the node counts of the shorthand node groups are the nodes which joined the cluster
+	cr.Status.ESMaster = getJoinedNodes(nodesPerNodeSet, "master", cr.Spec.ESMaster != nil)
+	cr.Status.ESData = getJoinedNodes(nodesPerNodeSet, "data", cr.Spec.ESData != nil)
+	cr.Status.ESIngestion = getJoinedNodes(nodesPerNodeSet, "ingestion", cr.Spec.ESIngestion != nil)
+	cr.Status.ESClient = getJoinedNodes(nodesPerNodeSet, "client", cr.Spec.ESClient != nil)
+}
+
+// getNodeSetOfNode is a method to find the node set of a node, nodes are named after their pods
+func getNodeSetOfNode(cr *loggingv1beta1.Elasticsearch, nodeName string) string {
+	for _, role := range getNodeSetNames(cr) {
+		prefix := fmt.Sprintf("%s-%s-", cr.ObjectMeta.Name, role)
+		if !strings.HasPrefix(nodeName, prefix) {
+			continue
+		}
+		// node set names may share a prefix, like data and data-hot
+		if ordinal := strings.TrimPrefix(nodeName, prefix); strings.Trim(ordinal, "0123456789") == "" {
+			return role
+		}
+	}
+	return ""
+}
+
+// getJoinedNodes is a method to get the joined nodes of a shorthand node group which is configured
+func getJoinedNodes(nodesPerNodeSet map[string]int32, role string, configured bool) *int32 {
+	if !configured {
+		return nil
+	}
+	nodes := nodesPerNodeSet[role]
+	return &nodes
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// Modes to raise vm.max_map_count which are supported in the elasticsearch spec
+const (
+	vmMaxMapCountInitContainer = "InitContainer"
+	vmMaxMapCountDaemonSet     = "DaemonSet"
+	vmMaxMapCountDisableMmap   = "DisableMmap"
+	vmMaxMapCountNone          = "None"
+)
+
+// vmMaxMapCount is the minimum of vm.max_map_count required by the bootstrap checks of elasticsearch
+const vmMaxMapCount = "262144"
+
+// sysctlScript raises vm.max_map_count on the node and keeps the pod running to raise it again after node restarts
+const sysctlScript = `if [ "$(cat /proc/sys/vm/max_map_count)" -lt ` + vmMaxMapCount + ` ]; then
+  sysctl -w vm.max_map_count=` + vmMaxMapCount + `
+fi
+trap 'exit 0' TERM INT
+while true; do
+  sleep 3600 &
+  wait $!
+done`
+
+// sysctlWaitScript holds elasticsearch startup until vm.max_map_count was raised on the node
+const sysctlWaitScript = `until [ "$(cat /proc/sys/vm/max_map_count)" -ge ` + vmMaxMapCount + ` ]; do
+  echo "Waiting for vm.max_map_count of the node to be raised to ` + vmMaxMapCount + `"
+  sleep 5
+done`
+
+// ReconcileElasticSysctlDaemonSet is a method to manage the daemonset which raises vm.max_map_count on the nodes
+func ReconcileElasticSysctlDaemonSet(cr *loggingv1beta1.Elasticsearch) error {
+	namespace, name := getSysctlDaemonSetName(cr)
+	if getVMMaxMapCountMode(cr) != vmMaxMapCountDaemonSet {
+		return k8sgo.DeleteDaemonSet(namespace, name)
+	}
+	labels := map[string]string{
+		"app":  name,
+		"role": "sysctl",
+	}
+	var kubernetesConfig *loggingv1beta1.KubernetesConfig
+	var image string
+	if nodeSets := GetNodeSets(cr); len(nodeSets) > 0 {
+		kubernetesConfig = nodeSets[0].KubernetesConfig
+		image = getElasticImage(cr, nodeSets[0].Name)
+	}
+	privileged := true
+	runAsUser := int64(0)
+	daemonSetParams := k8sgo.DaemonSetParameters{
+		DaemonSetMeta: k8sgo.GenerateObjectMetaInformation(name, namespace, labels, k8sgo.GenerateAnnotations()),
+		Namespace:     namespace,
+		ContainerParams: k8sgo.ContainerParams{
+			Name:            "sysctl",
+			Image:           image,
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(kubernetesConfig),
+			Command:         []string{"bash", "-c", sysctlScript},
+			VolumeMount:     &[]corev1.VolumeMount{},
+			SecurityContext: &corev1.SecurityContext{
+				Privileged: &privileged,
+				RunAsUser:  &runAsUser,
+			},
+		},
+		Labels:           labels,
+		Annotations:      k8sgo.GenerateAnnotations(),
+		ImagePullSecrets: k8sgo.GetImagePullSecrets(kubernetesConfig),
+		// elasticsearch may be scheduled on any node, tainted ones included
+		Tolerations: &[]corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+	}
+	// owner references can only point to objects of the same namespace, other namespaces are cleaned up on deletion
+	if namespace == cr.Namespace {
+		daemonSetParams.OwnerDef = k8sgo.ElasticAsOwner(cr)
+	}
+	return k8sgo.CreateOrUpdateDaemonSet(daemonSetParams)
+}
+
+// getVMMaxMapCountMode is a method to get the mode which raises vm.max_map_count for a cluster
+func getVMMaxMapCountMode(cr *loggingv1beta1.Elasticsearch) string {
+	if cr.Spec.VMMaxMapCount == nil || cr.Spec.VMMaxMapCount.Mode == "" {
+		return vmMaxMapCountInitContainer
+	}
+	return cr.Spec.VMMaxMapCount.Mode
+}
+
+// isUnprivilegedMode is a method to check if the elasticsearch pods run without the privileged init container
+func isUnprivilegedMode(cr *loggingv1beta1.Elasticsearch) bool {
+	return getVMMaxMapCountMode(cr) != vmMaxMapCountInitContainer
+}
+
+// getSysctlDaemonSetName is a method to get the namespace and name of the sysctl daemonset of a cluster
+func getSysctlDaemonSetName(cr *loggingv1beta1.Elasticsearch) (string, string) {
+	if cr.Spec.VMMaxMapCount != nil && cr.Spec.VMMaxMapCount.DaemonSetNamespace != "" && cr.Spec.VMMaxMapCount.DaemonSetNamespace != cr.Namespace {
+		// clusters of different namespaces may share the namespace of the daemonset
+		return cr.Spec.VMMaxMapCount.DaemonSetNamespace, fmt.Sprintf("%s-%s-sysctl", cr.Namespace, cr.ObjectMeta.Name)
+	}
+	return cr.Namespace, fmt.Sprintf("%s-sysctl", cr.ObjectMeta.Name)
+}
+
+// getSysctlWaitInitContainers is a method to hold elasticsearch startup until vm.max_map_count was raised outside of the pod
+func getSysctlWaitInitContainers(cr *loggingv1beta1.Elasticsearch, role string) []corev1.Container {
+	mode := getVMMaxMapCountMode(cr)
+	if mode != vmMaxMapCountDaemonSet && mode != vmMaxMapCountNone {
+		return nil
+	}
+	return []corev1.Container{
+		{
+			Name:            "sysctl-wait",
+			Image:           getElasticImage(cr, role),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(getNodeSetKubernetesConfig(cr, role)),
+			Command:         []string{"bash", "-c", sysctlWaitScript},
+		},
+	}
+}
+
+// getVMMaxMapCountEnvVariables is a method to disable memory mapping of the store when vm.max_map_count is not raised
+func getVMMaxMapCountEnvVariables(cr *loggingv1beta1.Elasticsearch) []corev1.EnvVar {
+	if getVMMaxMapCountMode(cr) != vmMaxMapCountDisableMmap {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: "node.store.allow_mmap", Value: "false"}}
+}
+
+// getPodSecurityContext is a method to get the pod security context of a node set
+func getPodSecurityContext(cr *loggingv1beta1.Elasticsearch, role string) *corev1.PodSecurityContext {
+	kubernetesConfig := getNodeSetKubernetesConfig(cr, role)
+	if kubernetesConfig != nil && kubernetesConfig.SecurityContext != nil {
+		return kubernetesConfig.SecurityContext
+	}
+	if !isUnprivilegedMode(cr) {
+		return nil
+	}
+	// the defaults pass the restricted pod security standard with the user of the elasticsearch image
+	runAsNonRoot := true
+	user := int64(1000)
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot:   &runAsNonRoot,
+		RunAsUser:      &user,
+		FSGroup:        &user,
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// getContainerSecurityContext is a method to get the security context of the containers of a node set
+func getContainerSecurityContext(cr *loggingv1beta1.Elasticsearch, role string) *corev1.SecurityContext {
+	kubernetesConfig := getNodeSetKubernetesConfig(cr, role)
+	if kubernetesConfig != nil && kubernetesConfig.ContainerSecurityContext != nil {
+		return kubernetesConfig.ContainerSecurityContext
+	}
+	if !isUnprivilegedMode(cr) {
+		return nil
+	}
+	allowPrivilegeEscalation := false
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+}
+
+// withContainerSecurityContext is a method to apply a security context to containers generated by the operator
+func withContainerSecurityContext(containers []corev1.Container, securityContext *corev1.SecurityContext) []corev1.Container {
+	if securityContext == nil {
+		return containers
+	}
+	for i := range containers {
+		containers[i].SecurityContext = securityContext.DeepCopy()
+	}
+	return containers
+}
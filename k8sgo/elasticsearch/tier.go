@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"sort"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+)
+
+// dataTierRoles is the list of data roles for which the status is reported
+var dataTierRoles = []loggingv1beta1.NodeRole{"data", "data_content", "data_hot", "data_warm", "data_cold", "data_frozen"}
+
+// GetDataTierStatus is a method to get the node count and disk usage of every data tier
+func GetDataTierStatus(cr *loggingv1beta1.Elasticsearch) ([]loggingv1beta1.DataTierStatus, error) {
+	var tiers []loggingv1beta1.DataTierStatus
+	nodes, err := elasticgo.GetElasticNodeStats(cr)
+	if err != nil {
+		return nil, err
+	}
+	for _, tierRole := range dataTierRoles {
+		tier := loggingv1beta1.DataTierStatus{Tier: string(tierRole)}
+		for _, node := range nodes {
+			if !containsString(node.Roles, string(tierRole)) {
+				continue
+			}
+			tier.Nodes++
+			tier.DiskTotalBytes += node.FS.Total.TotalInBytes
+			tier.DiskUsedBytes += node.FS.Total.TotalInBytes - node.FS.Total.AvailableInBytes
+		}
+		if tier.Nodes == 0 {
+			continue
+		}
+		if tier.DiskTotalBytes > 0 {
+			tier.DiskUsedPercent = int32(tier.DiskUsedBytes * 100 / tier.DiskTotalBytes)
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// ValidateDataTiers is a method to check that the node sets can serve the tier routing of elasticsearch
+func ValidateDataTiers(cr *loggingv1beta1.Elasticsearch) []string {
+	var warnings []string
+	tierNodeSets := map[loggingv1beta1.NodeRole][]loggingv1beta1.NodeSet{}
+	for _, nodeSet := range GetNodeSets(cr) {
+		for _, tierRole := range dataTierRoles {
+			if hasNodeRole(nodeSet, tierRole) {
+				tierNodeSets[tierRole] = append(tierNodeSets[tierRole], nodeSet)
+			}
+		}
+	}
+	usesTiers := len(tierNodeSets["data_hot"])+len(tierNodeSets["data_warm"])+len(tierNodeSets["data_cold"])+len(tierNodeSets["data_frozen"]) > 0
+	if !usesTiers {
+		return nil
+	}
+	// nodes with the generic data role belong to every tier
+	if len(tierNodeSets["data"]) == 0 {
+		if len(tierNodeSets["data_content"]) == 0 {
+			warnings = append(warnings, "no node set has the data_content role, regular indices can not be allocated")
+		}
+		if len(tierNodeSets["data_hot"]) == 0 {
+			warnings = append(warnings, "no node set has the data_hot role, data streams can not be allocated")
+		}
+	}
+
+	// an attribute used for allocation filtering has to separate the hot, warm, cold and frozen tiers
+	attributeTiers := map[string]map[string]loggingv1beta1.NodeRole{}
+	for _, tierRole := range dataTierRoles[2:] {
+		for _, nodeSet := range tierNodeSets[tierRole] {
+			for key, value := range nodeSet.NodeAttributes {
+				if attributeTiers[key] == nil {
+					attributeTiers[key] = map[string]loggingv1beta1.NodeRole{}
+				}
+				if otherTier, ok := attributeTiers[key][value]; ok && otherTier != tierRole {
+					warnings = append(warnings, fmt.Sprintf("node attribute %s=%s is used by the %s and %s tiers", key, value, otherTier, tierRole))
+					continue
+				}
+				attributeTiers[key][value] = tierRole
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+)
+
+const (
+	tlsFormatPKCS12 = "PKCS12"
+	keystoreName    = "keystore.p12"
+)
+
+// tlsLayers is the list of elasticsearch layers which can be secured by TLS
+var tlsLayers = []string{"http", "transport"}
+
+// IsGeneratedCertificateRequired is a method to check if the operator has to sign certificates itself
+func IsGeneratedCertificateRequired(cr *loggingv1beta1.Elasticsearch) bool {
+	return cr.Spec.Security != nil && cr.Spec.Security.IssuerRef == nil && len(getManagedCertificateLayers(cr)) > 0
+}
+
+// getManagedCertificateLayers is a method to list the TLS layers without a user provided certificate
+func getManagedCertificateLayers(cr *loggingv1beta1.Elasticsearch) []string {
+	var layers []string
+	for _, layer := range tlsLayers {
+		if isTLSLayerEnabled(cr, layer) && getUserCertificate(cr, layer) == nil {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// isTLSLayerEnabled is a method to check if TLS is enabled for a layer
+func isTLSLayerEnabled(cr *loggingv1beta1.Elasticsearch, layer string) bool {
+	if layer == "http" {
+		return cr.Spec.Security.IsHTTPTLSEnabled()
+	}
+	return cr.Spec.Security.IsTransportTLSEnabled()
+}
+
+// getUserCertificate is a method to get the user provided certificate of a layer
+func getUserCertificate(cr *loggingv1beta1.Elasticsearch, layer string) *loggingv1beta1.TLSLayerConfig {
+	if cr.Spec.Security == nil {
+		return nil
+	}
+	layerConfig := cr.Spec.Security.Transport
+	if layer == "http" {
+		layerConfig = cr.Spec.Security.HTTP
+	}
+	if layerConfig == nil || layerConfig.SecretName == nil {
+		return nil
+	}
+	return layerConfig
+}
+
+// getTLSLayerPath is a method to get the directory in which the certificate of a layer is mounted
+func getTLSLayerPath(cr *loggingv1beta1.Elasticsearch, layer string) string {
+	if IsGeneratedCertificateRequired(cr) && getUserCertificate(cr, layer) == nil {
+		return elasticCertPath
+	}
+	// secret volumes are read-only, so layer certificates can not be nested below the generated ones
+	return fmt.Sprintf("%s-%s", elasticCertPath, layer)
+}
+
+// getLayerSecretName is a method to get the secret of a layer which is not covered by generated certificates
+func getLayerSecretName(cr *loggingv1beta1.Elasticsearch, layer string) string {
+	if !isTLSLayerEnabled(cr, layer) {
+		return ""
+	}
+	if userCertificate := getUserCertificate(cr, layer); userCertificate != nil {
+		return *userCertificate.SecretName
+	}
+	if IsIssuerEnabled(cr) {
+		return fmt.Sprintf("%s-%s-cert", cr.ObjectMeta.Name, layer)
+	}
+	return ""
+}
+
+// getTLSVolumes is a method to define the certificate volumes of elasticsearch
+func getTLSVolumes(cr *loggingv1beta1.Elasticsearch) []corev1.Volume {
+	var volumes []corev1.Volume
+	if IsGeneratedCertificateRequired(cr) {
+		volumes = append(volumes, corev1.Volume{
+			Name: "tls-cert",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: fmt.Sprintf("%s-tls-cert", cr.ObjectMeta.Name),
+				},
+			},
+		})
+	}
+	for _, layer := range tlsLayers {
+		if secretName := getLayerSecretName(cr, layer); secretName != "" {
+			volumes = append(volumes, corev1.Volume{
+				Name: fmt.Sprintf("%s-cert", layer),
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: secretName,
+					},
+				},
+			})
+		}
+	}
+	return volumes
+}
+
+// getTLSVolumeMounts is a method to mount the certificate volumes in elasticsearch
+func getTLSVolumeMounts(cr *loggingv1beta1.Elasticsearch) []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
+	if IsGeneratedCertificateRequired(cr) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "tls-cert",
+			MountPath: elasticCertPath,
+		})
+	}
+	for _, layer := range tlsLayers {
+		if getLayerSecretName(cr, layer) != "" {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      fmt.Sprintf("%s-cert", layer),
+				MountPath: getTLSLayerPath(cr, layer),
+			})
+		}
+	}
+	return volumeMounts
+}
+
+// getTLSEnvVariables is a method to create TLS environment variables for elasticsearch
+func getTLSEnvVariables(cr *loggingv1beta1.Elasticsearch) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	if cr.Spec.Security.IsHTTPTLSEnabled() || cr.Spec.Security.IsTransportTLSEnabled() {
+		envVars = append(envVars, corev1.EnvVar{Name: "xpack.security.enabled", Value: "true"})
+	}
+	for _, layer := range tlsLayers {
+		if !isTLSLayerEnabled(cr, layer) {
+			continue
+		}
+		settingPrefix := fmt.Sprintf("xpack.security.%s.ssl", layer)
+		certPath := getTLSLayerPath(cr, layer)
+		envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.enabled", settingPrefix), Value: "true"})
+		if layer == "transport" {
+			envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.verification_mode", settingPrefix), Value: "certificate"})
+		}
+		userCertificate := getUserCertificate(cr, layer)
+		if userCertificate != nil && userCertificate.Format == tlsFormatPKCS12 {
+			envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.keystore.path", settingPrefix), Value: fmt.Sprintf("%s/%s", certPath, keystoreName)})
+			envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.truststore.path", settingPrefix), Value: fmt.Sprintf("%s/%s", certPath, keystoreName)})
+			continue
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.key", settingPrefix), Value: fmt.Sprintf("%s/%s", certPath, certificateKeyName)})
+		envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.certificate", settingPrefix), Value: fmt.Sprintf("%s/%s", certPath, certificateCertName)})
+		envVars = append(envVars, corev1.EnvVar{Name: fmt.Sprintf("%s.certificate_authorities", settingPrefix), Value: fmt.Sprintf("%s/%s", certPath, certificateCAName)})
+	}
+	return envVars
+}
+
+// getTLSKeystoreSecrets is a method to add the passwords of user certificates to the keystore as secure settings
+func getTLSKeystoreSecrets(cr *loggingv1beta1.Elasticsearch) []loggingv1beta1.KeystoreSecret {
+	var secrets []loggingv1beta1.KeystoreSecret
+	for _, layer := range tlsLayers {
+		if !isTLSLayerEnabled(cr, layer) {
+			continue
+		}
+		userCertificate := getUserCertificate(cr, layer)
+		if userCertificate == nil || userCertificate.PasswordSecret == nil {
+			continue
+		}
+		settingPrefix := fmt.Sprintf("xpack.security.%s.ssl", layer)
+		passwordKey := userCertificate.PasswordSecret.Key
+		items := []loggingv1beta1.KeystoreItem{{Key: passwordKey, Setting: fmt.Sprintf("%s.secure_key_passphrase", settingPrefix)}}
+		if userCertificate.Format == tlsFormatPKCS12 {
+			items = []loggingv1beta1.KeystoreItem{
+				{Key: passwordKey, Setting: fmt.Sprintf("%s.keystore.secure_password", settingPrefix)},
+				{Key: passwordKey, Setting: fmt.Sprintf("%s.truststore.secure_password", settingPrefix)},
+			}
+		}
+		secrets = append(secrets, loggingv1beta1.KeystoreSecret{SecretName: userCertificate.PasswordSecret.Name, Items: items})
+	}
+	return secrets
+}
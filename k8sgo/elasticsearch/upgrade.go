@@ -0,0 +1,320 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/elasticgo"
+	"logging-operator/k8sgo"
+)
+
+// Upgrade phases reported in the elasticsearch status
+const (
+	upgradePhasePreparing  = "Preparing"
+	upgradePhaseRestarting = "Restarting"
+	upgradePhaseFinalizing = "Finalizing"
+	upgradePhaseCompleted  = "Completed"
+	upgradePhaseRejected   = "Rejected"
+)
+
+// lastMinorVersions is the minor version from which an upgrade to the next major version is supported
+var lastMinorVersions = map[int]int{6: 8, 7: 17}
+
+// ReconcileElasticUpgrade is a method to restart elasticsearch nodes one by one when esVersion changes
+func ReconcileElasticUpgrade(cr *loggingv1beta1.Elasticsearch) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	if cr.Status.ESVersion == "" {
+		cr.Status.ESVersion = cr.Spec.ESVersion
+		return nil
+	}
+	upgrade := cr.Status.Upgrade
+	if isUpgradeInProgress(cr) {
+		if upgrade.TargetVersion != cr.Spec.ESVersion {
+			upgrade.Message = fmt.Sprintf("upgrade to %s is in progress, version %s is applied afterwards", upgrade.TargetVersion, cr.Spec.ESVersion)
+		}
+		return reconcileUpgradePhase(cr)
+	}
+	// nodes are not restarted while shards are moved away from nodes which are removed
+	if cr.Status.ESVersion == cr.Spec.ESVersion || cr.Status.ScaleDown != nil {
+		return nil
+	}
+	if upgrade != nil && upgrade.Phase == upgradePhaseRejected && upgrade.TargetVersion == cr.Spec.ESVersion {
+		return nil
+	}
+	cr.Status.Upgrade = &loggingv1beta1.UpgradeStatus{
+		FromVersion:   cr.Status.ESVersion,
+		TargetVersion: cr.Spec.ESVersion,
+		Phase:         upgradePhasePreparing,
+		TotalNodes:    getTotalNodes(cr),
+	}
+	if err := validateUpgradePath(cr.Status.ESVersion, cr.Spec.ESVersion); err != nil {
+		logger.Info("Elasticsearch upgrade is rejected", "reason", err.Error())
+		cr.Status.Upgrade.Phase = upgradePhaseRejected
+		cr.Status.Upgrade.Message = err.Error()
+		return nil
+	}
+	logger.Info("Elasticsearch upgrade is started", "from", cr.Status.ESVersion, "to", cr.Spec.ESVersion)
+	return nil
+}
+
+// reconcileUpgradePhase is a method to move the upgrade state machine one step forward
+func reconcileUpgradePhase(cr *loggingv1beta1.Elasticsearch) error {
+	logger := k8sgo.LogGenerator(cr.ObjectMeta.Name, cr.Namespace, "Elasticsearch")
+	upgrade := cr.Status.Upgrade
+	switch upgrade.Phase {
+	case upgradePhasePreparing:
+		clusterInfo, err := elasticgo.GetElasticClusterDetails(cr)
+		if err != nil {
+			return err
+		}
+		if clusterInfo.ClusterState != "green" {
+			upgrade.Message = "waiting for cluster health to become green before the upgrade"
+			return nil
+		}
+		upgrade.Phase = upgradePhaseRestarting
+		upgrade.Message = ""
+		return nil
+	case upgradePhaseRestarting:
+		if upgrade.CurrentNode != "" {
+			updated, err := isNodeUpdated(cr, upgrade.CurrentRole, upgrade.CurrentNode)
+			if err != nil || !updated {
+				return err
+			}
+			err = elasticgo.SetShardAllocation(cr, "")
+			if err != nil {
+				return err
+			}
+			clusterInfo, err := elasticgo.GetElasticClusterDetails(cr)
+			if err != nil {
+				return err
+			}
+			if clusterInfo.ClusterState != "green" {
+				upgrade.Message = fmt.Sprintf("waiting for cluster health to become green after restart of %s", upgrade.CurrentNode)
+				return nil
+			}
+			logger.Info("Elasticsearch node is upgraded", "node", upgrade.CurrentNode)
+			upgrade.UpgradedNodes++
+			upgrade.CurrentNode = ""
+			upgrade.Message = ""
+		}
+		role, partition, found := getNextUpgradeNode(cr)
+		if !found {
+			upgrade.Phase = upgradePhaseFinalizing
+			return nil
+		}
+		// replicas would be recovered to other nodes while the node is restarting
+		err := elasticgo.SetShardAllocation(cr, "primaries")
+		if err != nil {
+			return err
+		}
+		err = elasticgo.FlushIndices(cr)
+		if err != nil {
+			return err
+		}
+		upgrade.CurrentRole = role
+		upgrade.Partition = partition
+		upgrade.CurrentNode = fmt.Sprintf("%s-%s-%d", cr.ObjectMeta.Name, role, partition)
+		logger.Info("Restarting elasticsearch node for upgrade", "node", upgrade.CurrentNode)
+		return nil
+	case upgradePhaseFinalizing:
+		err := elasticgo.SetShardAllocation(cr, "")
+		if err != nil {
+			return err
+		}
+		clusterInfo, err := elasticgo.GetElasticClusterDetails(cr)
+		if err != nil {
+			return err
+		}
+		if clusterInfo.ClusterState != "green" {
+			upgrade.Message = "waiting for cluster health to become green after the upgrade"
+			return nil
+		}
+		logger.Info("Elasticsearch upgrade is completed", "version", upgrade.TargetVersion)
+		cr.Status.ESVersion = upgrade.TargetVersion
+		upgrade.Phase = upgradePhaseCompleted
+		upgrade.CurrentRole = ""
+		upgrade.Partition = 0
+		upgrade.Message = ""
+	}
+	return nil
+}
+
+// isUpgradeInProgress is a method to check if nodes are being upgraded
+func isUpgradeInProgress(cr *loggingv1beta1.Elasticsearch) bool {
+	if cr.Status.Upgrade == nil {
+		return false
+	}
+	switch cr.Status.Upgrade.Phase {
+	case upgradePhasePreparing, upgradePhaseRestarting, upgradePhaseFinalizing:
+		return true
+	}
+	return false
+}
+
+// getElasticVersion is a method to get the elasticsearch version which should be deployed
+func getElasticVersion(cr *loggingv1beta1.Elasticsearch) string {
+	if isUpgradeInProgress(cr) {
+		return cr.Status.Upgrade.TargetVersion
+	}
+	// a new version is only rolled out by the upgrade state machine
+	if cr.Status.ESVersion != "" {
+		return cr.Status.ESVersion
+	}
+	return cr.Spec.ESVersion
+}
+
+// getUpgradePartition is a method to get the statefulset partition which holds back pods during an upgrade
+func getUpgradePartition(cr *loggingv1beta1.Elasticsearch, role string, replicas int32) *int32 {
+	if !isUpgradeInProgress(cr) || cr.Status.Upgrade.Phase == upgradePhaseFinalizing {
+		return nil
+	}
+	var partition int32
+	upgrade := cr.Status.Upgrade
+	switch {
+	case upgrade.CurrentRole == "" || getUpgradeNodeSetIndex(cr, role) > getUpgradeNodeSetIndex(cr, upgrade.CurrentRole):
+		partition = replicas
+	case role == upgrade.CurrentRole:
+		partition = upgrade.Partition
+	}
+	return &partition
+}
+
+// getNextUpgradeNode is a method to find the next node which has to be restarted
+func getNextUpgradeNode(cr *loggingv1beta1.Elasticsearch) (string, int32, bool) {
+	upgrade := cr.Status.Upgrade
+	if upgrade.CurrentRole != "" && upgrade.Partition > 0 {
+		return upgrade.CurrentRole, upgrade.Partition - 1, true
+	}
+	for _, role := range getUpgradeNodeSets(cr) {
+		if upgrade.CurrentRole != "" && getUpgradeNodeSetIndex(cr, role) <= getUpgradeNodeSetIndex(cr, upgrade.CurrentRole) {
+			continue
+		}
+		if replicas := getNodeSetReplicas(cr, role); replicas > 0 {
+			return role, replicas - 1, true
+		}
+	}
+	return "", 0, false
+}
+
+// isNodeUpdated is a method to check if a restarted node runs the latest revision and is ready
+func isNodeUpdated(cr *loggingv1beta1.Elasticsearch, role string, podName string) (bool, error) {
+	stateful, err := k8sgo.GetStateFulSet(cr.Namespace, fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role))
+	if err != nil {
+		return false, err
+	}
+	pod, err := k8sgo.GetPod(cr.Namespace, podName)
+	if err != nil {
+		return false, nil
+	}
+	return pod.Labels["controller-revision-hash"] == stateful.Status.UpdateRevision && k8sgo.IsPodReady(pod), nil
+}
+
+// getUpgradeNodeSets is a method to list the node sets in restart order, data nodes first and masters last
+func getUpgradeNodeSets(cr *loggingv1beta1.Elasticsearch) []string {
+	var names []string
+	nodeSets := GetNodeSets(cr)
+	sort.SliceStable(nodeSets, func(i, j int) bool {
+		return getUpgradeRank(nodeSets[i]) < getUpgradeRank(nodeSets[j])
+	})
+	for _, nodeSet := range nodeSets {
+		names = append(names, nodeSet.Name)
+	}
+	return names
+}
+
+// getUpgradeRank is a method to get the restart priority of a node set
+func getUpgradeRank(nodeSet loggingv1beta1.NodeSet) int {
+	switch {
+	case hasNodeRole(nodeSet, "master"):
+		return 2
+	case isDataNodeSet(nodeSet):
+		return 0
+	}
+	return 1
+}
+
+// getUpgradeNodeSetIndex is a method to get the position of a node set in the restart order
+func getUpgradeNodeSetIndex(cr *loggingv1beta1.Elasticsearch, name string) int {
+	for index, nodeSetName := range getUpgradeNodeSets(cr) {
+		if nodeSetName == name {
+			return index
+		}
+	}
+	return -1
+}
+
+// getTotalNodes is a method to count the nodes of the cluster
+func getTotalNodes(cr *loggingv1beta1.Elasticsearch) int32 {
+	var total int32
+	for _, name := range getNodeSetNames(cr) {
+		total += getNodeSetReplicas(cr, name)
+	}
+	return total
+}
+
+// validateUpgradePath is a method to check that elasticsearch supports an upgrade between two versions
+func validateUpgradePath(fromVersion string, toVersion string) error {
+	from, err := parseVersion(fromVersion)
+	if err != nil {
+		return err
+	}
+	to, err := parseVersion(toVersion)
+	if err != nil {
+		return err
+	}
+	for index := range from {
+		if to[index] != from[index] {
+			if to[index] < from[index] {
+				return fmt.Errorf("downgrade from %s to %s is not supported", fromVersion, toVersion)
+			}
+			break
+		}
+	}
+	switch to[0] - from[0] {
+	case 0:
+		return nil
+	case 1:
+		lastMinor, ok := lastMinorVersions[from[0]]
+		if !ok {
+			return fmt.Errorf("upgrade from %s to %s is not supported", fromVersion, toVersion)
+		}
+		if from[1] < lastMinor {
+			return fmt.Errorf("upgrade to %s requires version %d.%d first", toVersion, from[0], lastMinor)
+		}
+		return nil
+	}
+	return fmt.Errorf("upgrade from %s to %s skips a major version", fromVersion, toVersion)
+}
+
+// parseVersion is a method to split an elasticsearch version into major, minor and patch
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.SplitN(strings.SplitN(version, "-", 2)[0], ".", 3)
+	for index, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, fmt.Errorf("invalid elasticsearch version %s", version)
+		}
+		parsed[index] = number
+	}
+	return parsed, nil
+}
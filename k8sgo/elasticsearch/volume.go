@@ -0,0 +1,183 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// Volume expansion phases reported in the elasticsearch status
+const (
+	volumeExpansionPhaseResizing                = "Resizing"
+	volumeExpansionPhaseFileSystemResizePending = "FileSystemResizePending"
+	volumeExpansionPhaseUnsupported             = "Unsupported"
+)
+
+// ReconcileElasticVolumeExpansion is a method to expand the volumes of node sets whose storage size was increased
+func ReconcileElasticVolumeExpansion(cr *loggingv1beta1.Elasticsearch) error {
+	var expansionStatus []loggingv1beta1.VolumeExpansionStatus
+	for _, nodeSet := range GetNodeSets(cr) {
+		if nodeSet.Storage == nil {
+			continue
+		}
+		appName := fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, nodeSet.Name)
+		stateful, err := k8sgo.GetStateFulSet(cr.Namespace, appName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		nodeStatus, err := expandNodeSetVolumes(cr, stateful, nodeSet)
+		if err != nil {
+			return err
+		}
+		expansionStatus = append(expansionStatus, nodeStatus...)
+	}
+	cr.Status.VolumeExpansion = expansionStatus
+	return nil
+}
+
+// expandNodeSetVolumes is a method to resize the claims of a node set and recreate its statefulset with the new claim template
+func expandNodeSetVolumes(cr *loggingv1beta1.Elasticsearch, stateful *appsv1.StatefulSet, nodeSet loggingv1beta1.NodeSet) ([]loggingv1beta1.VolumeExpansionStatus, error) {
+	logger := k8sgo.LogGenerator(stateful.Name, cr.Namespace, "PersistentVolumeClaim")
+	claimTemplate := getClaimTemplate(stateful)
+	if claimTemplate == nil {
+		return nil, nil
+	}
+	replicas := int32(1)
+	if stateful.Spec.Replicas != nil {
+		replicas = *stateful.Spec.Replicas
+	}
+	if stateful.DeletionTimestamp != nil {
+		return getVolumeExpansionStatus(cr, stateful, claimTemplate, replicas)
+	}
+	requestedSize, err := resource.ParseQuantity(nodeSet.Storage.StorageSize)
+	if err != nil {
+		return nil, err
+	}
+	templateSize := claimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]
+
+	switch requestedSize.Cmp(templateSize) {
+	case -1:
+		logger.Info("Shrinking volumes is not supported, keeping the current size", "size", templateSize.String())
+		return nil, nil
+	case 1:
+		expandable, err := k8sgo.IsStorageClassExpandable(claimTemplate.Spec.StorageClassName)
+		if err != nil {
+			return nil, err
+		}
+		if !expandable {
+			logger.Info("Storage class does not allow volume expansion", "size", requestedSize.String())
+			var expansionStatus []loggingv1beta1.VolumeExpansionStatus
+			for ordinal := int32(0); ordinal < replicas; ordinal++ {
+				expansionStatus = append(expansionStatus, loggingv1beta1.VolumeExpansionStatus{
+					Pod:           fmt.Sprintf("%s-%d", stateful.Name, ordinal),
+					Claim:         getClaimName(stateful, claimTemplate, ordinal),
+					RequestedSize: requestedSize.String(),
+					CurrentSize:   templateSize.String(),
+					Phase:         volumeExpansionPhaseUnsupported,
+					Message:       "storage class does not allow volume expansion",
+				})
+			}
+			return expansionStatus, nil
+		}
+		for ordinal := int32(0); ordinal < replicas; ordinal++ {
+			pvc, err := k8sgo.GetPersistentVolumeClaim(cr.Namespace, getClaimName(stateful, claimTemplate, ordinal))
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if pvc.Spec.Resources.Requests.Storage().Cmp(requestedSize) < 0 {
+				err = k8sgo.ResizePersistentVolumeClaim(pvc, requestedSize)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		// claim templates are immutable, the statefulset is created again from the new template without touching the pods
+		logger.Info("Recreating statefulset with the expanded claim template", "size", requestedSize.String())
+		err = k8sgo.DeleteStateFulSetOrphan(cr.Namespace, stateful.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return getVolumeExpansionStatus(cr, stateful, claimTemplate, replicas)
+}
+
+// getVolumeExpansionStatus is a method to list the claims of a statefulset whose capacity is below the requested size
+func getVolumeExpansionStatus(cr *loggingv1beta1.Elasticsearch, stateful *appsv1.StatefulSet, claimTemplate *corev1.PersistentVolumeClaim, replicas int32) ([]loggingv1beta1.VolumeExpansionStatus, error) {
+	var expansionStatus []loggingv1beta1.VolumeExpansionStatus
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		pvc, err := k8sgo.GetPersistentVolumeClaim(cr.Namespace, getClaimName(stateful, claimTemplate, ordinal))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		requested := pvc.Spec.Resources.Requests.Storage()
+		capacity := pvc.Status.Capacity.Storage()
+		if capacity.Cmp(*requested) >= 0 {
+			continue
+		}
+		podStatus := loggingv1beta1.VolumeExpansionStatus{
+			Pod:           fmt.Sprintf("%s-%d", stateful.Name, ordinal),
+			Claim:         pvc.Name,
+			RequestedSize: requested.String(),
+			CurrentSize:   capacity.String(),
+			Phase:         volumeExpansionPhaseResizing,
+		}
+		for _, condition := range pvc.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending {
+				podStatus.Phase = volumeExpansionPhaseFileSystemResizePending
+			}
+			if condition.Message != "" {
+				podStatus.Message = condition.Message
+			}
+		}
+		expansionStatus = append(expansionStatus, podStatus)
+	}
+	return expansionStatus, nil
+}
+
+// getClaimTemplate is a method to get the data volume claim template of a statefulset
+func getClaimTemplate(stateful *appsv1.StatefulSet) *corev1.PersistentVolumeClaim {
+	for i := range stateful.Spec.VolumeClaimTemplates {
+		if stateful.Spec.VolumeClaimTemplates[i].Name == stateful.Name {
+			return &stateful.Spec.VolumeClaimTemplates[i]
+		}
+	}
+	return nil
+}
+
+// getClaimName is a method to get the name of the claim which a statefulset creates for a pod
+func getClaimName(stateful *appsv1.StatefulSet, claimTemplate *corev1.PersistentVolumeClaim, ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d", claimTemplate.Name, stateful.Name, ordinal)
+}
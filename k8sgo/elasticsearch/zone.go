@@ -0,0 +1,191 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8selastic
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+const (
+	zoneAnnotation     = "logging.opstreelabs.in/zone"
+	zoneAttribute      = "zone"
+	zoneVolumeName     = "zone-info"
+	zoneMountPath      = "/usr/share/elasticsearch/zone"
+	defaultTopologyKey = "topology.kubernetes.io/zone"
+)
+
+// zoneWaitScript waits until the operator has copied the zone of the kubernetes node into the pod annotations
+const zoneWaitScript = `until [ -s ` + zoneMountPath + `/zone ]; do
+  echo "Waiting for zone of the node to be published"
+  sleep 2
+done
+echo "Node is running in zone $(cat ` + zoneMountPath + `/zone)"`
+
+// ReconcileElasticZones is a method to publish the zone of the kubernetes node to every elasticsearch pod
+func ReconcileElasticZones(cr *loggingv1beta1.Elasticsearch) error {
+	if !isZoneAwarenessEnabled(cr) {
+		return nil
+	}
+	topologyKey := getTopologyKey(cr)
+	for _, role := range getNodeSetNames(cr) {
+		pods, err := k8sgo.ListPods(cr.Namespace, getNodeSetLabels(cr, role))
+		if err != nil {
+			return err
+		}
+		for i := range pods {
+			pod := &pods[i]
+			// pods are only bound to a node after scheduling, and keep their zone for their whole life
+			if pod.Spec.NodeName == "" || pod.Annotations[zoneAnnotation] != "" {
+				continue
+			}
+			node, err := k8sgo.GetNode(pod.Spec.NodeName)
+			if err != nil {
+				return err
+			}
+			zone, ok := node.Labels[topologyKey]
+			if !ok || zone == "" {
+				return fmt.Errorf("node %s has no %s label", node.Name, topologyKey)
+			}
+			err = k8sgo.AnnotatePod(pod, zoneAnnotation, zone)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isZoneAwarenessEnabled is a method to check if nodes should be spread and allocated across zones
+func isZoneAwarenessEnabled(cr *loggingv1beta1.Elasticsearch) bool {
+	return cr.Spec.ZoneAwareness != nil
+}
+
+// getTopologyKey is a method to get the node label which contains the zone
+func getTopologyKey(cr *loggingv1beta1.Elasticsearch) string {
+	if cr.Spec.ZoneAwareness.TopologyKey != "" {
+		return cr.Spec.ZoneAwareness.TopologyKey
+	}
+	return defaultTopologyKey
+}
+
+// getNodeSetLabels is a method to get the labels of the pods of a node set
+func getNodeSetLabels(cr *loggingv1beta1.Elasticsearch, role string) map[string]string {
+	return map[string]string{
+		"app":  fmt.Sprintf("%s-%s", cr.ObjectMeta.Name, role),
+		"role": role,
+	}
+}
+
+// getTopologySpreadConstraints is a method to spread the pods of a node set evenly across zones
+func getTopologySpreadConstraints(cr *loggingv1beta1.Elasticsearch, labels map[string]string) []corev1.TopologySpreadConstraint {
+	if !isZoneAwarenessEnabled(cr) {
+		return nil
+	}
+	maxSkew := cr.Spec.ZoneAwareness.MaxSkew
+	if maxSkew < 1 {
+		maxSkew = 1
+	}
+	whenUnsatisfiable := cr.Spec.ZoneAwareness.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.DoNotSchedule
+	}
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           maxSkew,
+			TopologyKey:       getTopologyKey(cr),
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+		},
+	}
+}
+
+// getZoneInitContainers is a method to hold elasticsearch startup until the zone of the pod is known
+func getZoneInitContainers(cr *loggingv1beta1.Elasticsearch, role string) []corev1.Container {
+	if !isZoneAwarenessEnabled(cr) {
+		return nil
+	}
+	return []corev1.Container{
+		{
+			Name:            "zone-wait",
+			Image:           getElasticImage(cr, role),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(getNodeSetKubernetesConfig(cr, role)),
+			Command:         []string{"bash", "-c", zoneWaitScript},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      zoneVolumeName,
+					MountPath: zoneMountPath,
+				},
+			},
+		},
+	}
+}
+
+// getZoneVolumes is a method to expose the zone annotation of the pod to the init container
+func getZoneVolumes(cr *loggingv1beta1.Elasticsearch) []corev1.Volume {
+	if !isZoneAwarenessEnabled(cr) {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: zoneVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				DownwardAPI: &corev1.DownwardAPIVolumeSource{
+					Items: []corev1.DownwardAPIVolumeFile{
+						{
+							Path: "zone",
+							FieldRef: &corev1.ObjectFieldSelector{
+								FieldPath: fmt.Sprintf("metadata.annotations['%s']", zoneAnnotation),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getZoneEnvVariables is a method to generate the zone attribute and allocation awareness settings
+func getZoneEnvVariables(cr *loggingv1beta1.Elasticsearch) []corev1.EnvVar {
+	if !isZoneAwarenessEnabled(cr) {
+		return nil
+	}
+	envVars := []corev1.EnvVar{
+		{
+			Name: fmt.Sprintf("node.attr.%s", zoneAttribute),
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", zoneAnnotation),
+				},
+			},
+		},
+		{Name: "cluster.routing.allocation.awareness.attributes", Value: zoneAttribute},
+	}
+	// forced awareness keeps replicas unassigned instead of piling all copies into the surviving zones
+	if len(cr.Spec.ZoneAwareness.Zones) > 0 {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  fmt.Sprintf("cluster.routing.allocation.awareness.force.%s.values", zoneAttribute),
+			Value: strings.Join(cr.Spec.ZoneAwareness.Zones, ","),
+		})
+	}
+	return envVars
+}
@@ -36,14 +36,18 @@ func CreateFluentdDaemonSet(cr *loggingv1beta1.Fluentd) error {
 		OwnerDef:      k8sgo.FluentdAsOwner(cr),
 		DaemonSetMeta: k8sgo.GenerateObjectMetaInformation(appName, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
 		ContainerParams: k8sgo.ContainerParams{
-			Name:        "fluentd",
-			Image:       "fluent/fluentd-kubernetes-daemonset:v1-debian-elasticsearch",
-			VolumeMount: generateVolumeMounts(cr),
-			EnvVar:      generateEnvVariables(cr),
+			Name:            "fluentd",
+			Image:           k8sgo.GetFluentdImage(cr.Spec.KubernetesConfig),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(cr.Spec.KubernetesConfig),
+			VolumeMount:     generateVolumeMounts(cr),
+			EnvVar:          generateEnvVariables(cr),
 		},
-		Labels:      labels,
-		Annotations: k8sgo.GenerateAnnotations(),
-		Volumes:     getVolumes(cr),
+		Labels:             labels,
+		Annotations:        k8sgo.GenerateAnnotations(),
+		Volumes:            getVolumes(cr),
+		PodAnnotations:     getPodAnnotations(cr),
+		ImagePullSecrets:   k8sgo.GetImagePullSecrets(cr.Spec.KubernetesConfig),
+		ServiceAccountName: appName,
 	}
 	if cr.Spec.KubernetesConfig != nil {
 		daemonSetParams.Affinity = cr.Spec.KubernetesConfig.Affinity
@@ -51,6 +55,8 @@ func CreateFluentdDaemonSet(cr *loggingv1beta1.Fluentd) error {
 		daemonSetParams.PriorityClassName = cr.Spec.KubernetesConfig.PriorityClassName
 		daemonSetParams.Tolerations = cr.Spec.KubernetesConfig.Tolerations
 		daemonSetParams.ContainerParams.Resources = cr.Spec.KubernetesConfig.Resources
+		daemonSetParams.SecurityContext = cr.Spec.KubernetesConfig.SecurityContext
+		daemonSetParams.ContainerParams.SecurityContext = cr.Spec.KubernetesConfig.ContainerSecurityContext
 	} else {
 		daemonSetParams.Affinity = &corev1.Affinity{}
 		daemonSetParams.NodeSelector = map[string]string{}
@@ -74,11 +80,9 @@ func generateEnvVariables(cr *loggingv1beta1.Fluentd) []corev1.EnvVar {
 		{Name: "FLUENTD_SYSTEMD_CONF", Value: "disable"},
 	}
 	if cr.Spec.Security != nil {
-		if *cr.Spec.Security.TLSEnabled {
+		// security is enabled in elasticsearch as soon as one of the layers uses TLS
+		if cr.Spec.Security.IsHTTPTLSEnabled() || cr.Spec.Security.IsTransportTLSEnabled() {
 			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_USER", Value: "elastic"})
-			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SSL_VERIFY", Value: "false"})
-			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SSL_VERSION", Value: "TLSv1_2"})
-			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SCHEME", Value: "https"})
 			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{
 				Name: "FLUENT_ELASTICSEARCH_PASSWORD",
 				ValueFrom: &corev1.EnvVarSource{
@@ -91,6 +95,18 @@ func generateEnvVariables(cr *loggingv1beta1.Fluentd) []corev1.EnvVar {
 				},
 			})
 		}
+		if cr.Spec.Security.IsHTTPTLSEnabled() {
+			if isCAMountRequired(cr) {
+				fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SSL_VERIFY", Value: "true"})
+				fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_CA_FILE", Value: "/fluentd/etc/certs/ca.crt"})
+			} else if isUserHTTPCertificate(cr) {
+				fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SSL_VERIFY", Value: "true"})
+			} else {
+				fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SSL_VERIFY", Value: "false"})
+			}
+			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SSL_VERSION", Value: "TLSv1_2"})
+			fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_SCHEME", Value: "https"})
+		}
 	}
 	if cr.Spec.IndexNameStrategy != nil {
 		fluentdEnvVars = append(fluentdEnvVars, corev1.EnvVar{Name: "FLUENT_ELASTICSEARCH_LOGSTASH_PREFIX", Value: fmt.Sprintf("kubernetes-${record['kubernetes']['%s']}", *cr.Spec.IndexNameStrategy)})
@@ -101,6 +117,18 @@ func generateEnvVariables(cr *loggingv1beta1.Fluentd) []corev1.EnvVar {
 	return fluentdEnvVars
 }
 
+// getPodAnnotations is a method to restart Fluentd when the elasticsearch CA changes
+func getPodAnnotations(cr *loggingv1beta1.Fluentd) map[string]string {
+	annotations := map[string]string{}
+	if isCAMountRequired(cr) {
+		caHash, err := k8sgo.GetSecretHash(getCASecretName(cr), cr.Namespace)
+		if err == nil {
+			annotations["logging.opstreelabs.in/ca-hash"] = caHash
+		}
+	}
+	return annotations
+}
+
 // getVolumes is a method to define addtional volumes
 func getVolumes(cr *loggingv1beta1.Fluentd) *[]corev1.Volume {
 	volume := []corev1.Volume{
@@ -144,6 +172,19 @@ func getVolumes(cr *loggingv1beta1.Fluentd) *[]corev1.Volume {
 			},
 		})
 	}
+	if isCAMountRequired(cr) {
+		volume = append(volume, corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: getCASecretName(cr),
+					Items: []corev1.KeyToPath{
+						{Key: "ca.crt", Path: "ca.crt"},
+					},
+				},
+			},
+		})
+	}
 	if cr.Spec.AdditionalConfig != nil {
 		volume = append(volume, corev1.Volume{
 			Name: "fluentd-additional",
@@ -166,6 +207,13 @@ func generateVolumeMounts(cr *loggingv1beta1.Fluentd) *[]corev1.VolumeMount {
 		{Name: "varlibdockercontainers", MountPath: "/var/lib/docker/containers", ReadOnly: true},
 		{Name: "fluentd", MountPath: "/fluentd/etc/fluent.conf", SubPath: "fluent.conf"},
 	}
+	if isCAMountRequired(cr) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: "/fluentd/etc/certs",
+			ReadOnly:  true,
+		})
+	}
 	if cr.Spec.AdditionalConfig != nil {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      "fluentd-additional",
@@ -174,3 +222,24 @@ func generateVolumeMounts(cr *loggingv1beta1.Fluentd) *[]corev1.VolumeMount {
 	}
 	return &volumeMounts
 }
+
+// isCAMountRequired is a method to check if elasticsearch CA certificate should be mounted
+func isCAMountRequired(cr *loggingv1beta1.Fluentd) bool {
+	if cr.Spec.Security.IsHTTPTLSEnabled() {
+		return cr.Spec.ElasticConfig.ClusterName != "" && !isUserHTTPCertificate(cr)
+	}
+	return false
+}
+
+// isUserHTTPCertificate is a method to check if elasticsearch serves a user provided http certificate
+func isUserHTTPCertificate(cr *loggingv1beta1.Fluentd) bool {
+	return cr.Spec.Security.HTTP != nil && cr.Spec.Security.HTTP.SecretName != nil
+}
+
+// getCASecretName is a method to get the secret which contains the elasticsearch CA certificate
+func getCASecretName(cr *loggingv1beta1.Fluentd) string {
+	if cr.Spec.Security.IssuerRef != nil {
+		return fmt.Sprintf("%s-http-cert", cr.Spec.ElasticConfig.ClusterName)
+	}
+	return fmt.Sprintf("%s-ca-cert", cr.Spec.ElasticConfig.ClusterName)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sgo
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+)
+
+// Default images of the managed workloads, elasticsearch and kibana images are tagged with the version of the cluster
+const (
+	DefaultElasticsearchImage = "docker.elastic.co/elasticsearch/elasticsearch"
+	DefaultKibanaImage        = "docker.elastic.co/kibana/kibana"
+	DefaultFluentdImage       = "fluent/fluentd-kubernetes-daemonset:v1-debian-elasticsearch"
+)
+
+// ImageDefaults are the operator wide image settings which apply when a workload does not override them
+type ImageDefaults struct {
+	// Registry replaces the registry of the default images, e.g. with an internal mirror
+	Registry           string
+	ElasticsearchImage string
+	KibanaImage        string
+	FluentdImage       string
+	PullPolicy         corev1.PullPolicy
+	PullSecrets        []corev1.LocalObjectReference
+}
+
+var imageDefaults = ImageDefaults{
+	ElasticsearchImage: DefaultElasticsearchImage,
+	KibanaImage:        DefaultKibanaImage,
+	FluentdImage:       DefaultFluentdImage,
+}
+
+// SetImageDefaults is a method to set the operator wide image settings on startup
+func SetImageDefaults(defaults ImageDefaults) {
+	if defaults.ElasticsearchImage == "" {
+		defaults.ElasticsearchImage = DefaultElasticsearchImage
+	}
+	if defaults.KibanaImage == "" {
+		defaults.KibanaImage = DefaultKibanaImage
+	}
+	if defaults.FluentdImage == "" {
+		defaults.FluentdImage = DefaultFluentdImage
+	}
+	imageDefaults = defaults
+}
+
+// GetElasticsearchImage is a method to get the elasticsearch image of a workload in the version of the cluster
+func GetElasticsearchImage(config *loggingv1beta1.KubernetesConfig, version string) string {
+	return getImage(config, imageDefaults.ElasticsearchImage, version)
+}
+
+// GetKibanaImage is a method to get the kibana image of a workload in the version of the cluster
+func GetKibanaImage(config *loggingv1beta1.KubernetesConfig, version string) string {
+	return getImage(config, imageDefaults.KibanaImage, version)
+}
+
+// GetFluentdImage is a method to get the fluentd image of a workload
+func GetFluentdImage(config *loggingv1beta1.KubernetesConfig) string {
+	return getImage(config, imageDefaults.FluentdImage, "")
+}
+
+// GetImagePullPolicy is a method to get the image pull policy of a workload, an empty policy leaves the kubernetes default
+func GetImagePullPolicy(config *loggingv1beta1.KubernetesConfig) corev1.PullPolicy {
+	if config != nil && config.ImagePullPolicy != "" {
+		return config.ImagePullPolicy
+	}
+	return imageDefaults.PullPolicy
+}
+
+// GetImagePullSecrets is a method to get the image pull secrets of a workload
+func GetImagePullSecrets(config *loggingv1beta1.KubernetesConfig) []corev1.LocalObjectReference {
+	if config != nil && len(config.ImagePullSecrets) > 0 {
+		return config.ImagePullSecrets
+	}
+	return imageDefaults.PullSecrets
+}
+
+// getImage is a method to resolve the image of a workload, images without tag or digest are tagged with the version
+func getImage(config *loggingv1beta1.KubernetesConfig, defaultImage string, version string) string {
+	image := withImageRegistry(defaultImage)
+	if config != nil && config.Image != "" {
+		image = config.Image
+	}
+	if version != "" && !hasImageTag(image) {
+		image = image + ":" + version
+	}
+	return image
+}
+
+// withImageRegistry is a method to move a default image into the operator wide registry
+func withImageRegistry(image string) string {
+	if imageDefaults.Registry == "" {
+		return image
+	}
+	// the first path segment is a registry when it looks like a host, docker hub images have none
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image = parts[1]
+	}
+	return strings.TrimSuffix(imageDefaults.Registry, "/") + "/" + image
+}
+
+// hasImageTag is a method to check if an image reference contains a tag or digest
+func hasImageTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	return strings.Contains(image[strings.LastIndex(image, "/")+1:], ":")
+}
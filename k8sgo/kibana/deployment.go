@@ -38,15 +38,18 @@ func CreateKibanaSetup(cr *loggingv1beta1.Kibana) error {
 		Namespace:      cr.Namespace,
 		DeploymentMeta: k8sgo.GenerateObjectMetaInformation(appName, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
 		ContainerParams: k8sgo.ContainerParams{
-			Name:           "kibana",
-			Image:          fmt.Sprintf("docker.elastic.co/kibana/kibana:%s", cr.Spec.ElasticConfig.ESVersion),
-			VolumeMount:    getVolumeMounts(cr),
-			EnvVar:         generateEnvVariables(cr),
-			ReadinessProbe: createProbeInfo(),
+			Name:            "kibana",
+			Image:           k8sgo.GetKibanaImage(cr.Spec.KubernetesConfig, cr.Spec.ElasticConfig.ESVersion),
+			ImagePullPolicy: k8sgo.GetImagePullPolicy(cr.Spec.KubernetesConfig),
+			VolumeMount:     getVolumeMounts(cr),
+			EnvVar:          generateEnvVariables(cr),
+			ReadinessProbe:  createProbeInfo(),
 		},
-		Labels:      labels,
-		Annotations: k8sgo.GenerateAnnotations(),
-		Volumes:     getVolumes(cr),
+		Labels:           labels,
+		Annotations:      k8sgo.GenerateAnnotations(),
+		Volumes:          getVolumes(cr),
+		PodAnnotations:   getPodAnnotations(cr),
+		ImagePullSecrets: k8sgo.GetImagePullSecrets(cr.Spec.KubernetesConfig),
 	}
 	if cr.Spec.KubernetesConfig != nil {
 		deploymentParams.Affinity = cr.Spec.KubernetesConfig.Affinity
@@ -54,6 +57,8 @@ func CreateKibanaSetup(cr *loggingv1beta1.Kibana) error {
 		deploymentParams.PriorityClassName = cr.Spec.KubernetesConfig.PriorityClassName
 		deploymentParams.Tolerations = cr.Spec.KubernetesConfig.Tolerations
 		deploymentParams.ContainerParams.Resources = cr.Spec.KubernetesConfig.Resources
+		deploymentParams.SecurityContext = cr.Spec.KubernetesConfig.SecurityContext
+		deploymentParams.ContainerParams.SecurityContext = cr.Spec.KubernetesConfig.ContainerSecurityContext
 	} else {
 		deploymentParams.Affinity = &corev1.Affinity{}
 		deploymentParams.NodeSelector = map[string]string{}
@@ -68,20 +73,33 @@ func CreateKibanaSetup(cr *loggingv1beta1.Kibana) error {
 	return nil
 }
 
+// getPodAnnotations is a method to restart Kibana when the elasticsearch CA changes
+func getPodAnnotations(cr *loggingv1beta1.Kibana) map[string]string {
+	annotations := map[string]string{}
+	if isCAMountRequired(cr) {
+		caHash, err := k8sgo.GetSecretHash(getCASecretName(cr), cr.Namespace)
+		if err == nil {
+			annotations["logging.opstreelabs.in/ca-hash"] = caHash
+		}
+	}
+	return annotations
+}
+
 // getVolumes is a method to define addtional volumes
 func getVolumes(cr *loggingv1beta1.Kibana) *[]corev1.Volume {
 	var volumes []corev1.Volume
-	if cr.Spec.Security != nil {
-		if *cr.Spec.Security.TLSEnabled && cr.Spec.Security.TLSEnabled != nil {
-			volumes = append(volumes, corev1.Volume{
-				Name: "tls",
-				VolumeSource: corev1.VolumeSource{
-					Secret: &corev1.SecretVolumeSource{
-						SecretName: fmt.Sprintf("%s-tls-cert", cr.Spec.ElasticConfig.ClusterName),
+	if isCAMountRequired(cr) {
+		volumes = append(volumes, corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: getCASecretName(cr),
+					Items: []corev1.KeyToPath{
+						{Key: "ca.crt", Path: "ca.crt"},
 					},
 				},
-			})
-		}
+			},
+		})
 	}
 	return &volumes
 }
@@ -89,13 +107,11 @@ func getVolumes(cr *loggingv1beta1.Kibana) *[]corev1.Volume {
 // getVolumes is a method to define volumes mount
 func getVolumeMounts(cr *loggingv1beta1.Kibana) *[]corev1.VolumeMount {
 	var volumeMounts []corev1.VolumeMount
-	if cr.Spec.Security != nil {
-		if *cr.Spec.Security.TLSEnabled && cr.Spec.Security.TLSEnabled != nil {
-			volumeMounts = append(volumeMounts, corev1.VolumeMount{
-				Name:      "tls",
-				MountPath: "/usr/share/kibana/config/certs",
-			})
-		}
+	if isCAMountRequired(cr) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: "/usr/share/kibana/config/certs",
+		})
 	}
 	return &volumeMounts
 }
@@ -108,7 +124,8 @@ func generateEnvVariables(cr *loggingv1beta1.Kibana) []corev1.EnvVar {
 		{Name: "SERVER_NAME", Value: "kibana"},
 	}
 	if cr.Spec.Security != nil {
-		if *cr.Spec.Security.TLSEnabled && cr.Spec.Security.TLSEnabled != nil {
+		// security is enabled in elasticsearch as soon as one of the layers uses TLS
+		if cr.Spec.Security.IsHTTPTLSEnabled() || cr.Spec.Security.IsTransportTLSEnabled() {
 			kibanaEnvVars = append(kibanaEnvVars, corev1.EnvVar{Name: "ELASTIC_USERNAME", Value: "elastic"})
 			kibanaEnvVars = append(kibanaEnvVars, corev1.EnvVar{
 				Name: "ELASTIC_PASSWORD",
@@ -132,7 +149,12 @@ func generateEnvVariables(cr *loggingv1beta1.Kibana) []corev1.EnvVar {
 					},
 				},
 			})
-			kibanaEnvVars = append(kibanaEnvVars, corev1.EnvVar{Name: "ELASTICSEARCH_SSL_VERIFICATIONMODE", Value: "none"})
+		}
+		if cr.Spec.Security.IsHTTPTLSEnabled() {
+			if isCAMountRequired(cr) {
+				kibanaEnvVars = append(kibanaEnvVars, corev1.EnvVar{Name: "ELASTICSEARCH_SSL_CERTIFICATEAUTHORITIES", Value: "/usr/share/kibana/config/certs/ca.crt"})
+			}
+			kibanaEnvVars = append(kibanaEnvVars, corev1.EnvVar{Name: "ELASTICSEARCH_SSL_VERIFICATIONMODE", Value: "certificate"})
 		}
 	}
 	sort.SliceStable(kibanaEnvVars, func(i, j int) bool {
@@ -155,3 +177,20 @@ func createProbeInfo() *corev1.Probe {
 		},
 	}
 }
+
+// isCAMountRequired is a method to check if elasticsearch CA certificate should be mounted
+func isCAMountRequired(cr *loggingv1beta1.Kibana) bool {
+	if cr.Spec.Security.IsHTTPTLSEnabled() {
+		// user provided http certificates are expected to be trusted by the system truststore
+		return cr.Spec.Security.HTTP == nil || cr.Spec.Security.HTTP.SecretName == nil
+	}
+	return false
+}
+
+// getCASecretName is a method to get the secret which contains the elasticsearch CA certificate
+func getCASecretName(cr *loggingv1beta1.Kibana) string {
+	if cr.Spec.Security.IssuerRef != nil {
+		return fmt.Sprintf("%s-http-cert", cr.Spec.ElasticConfig.ClusterName)
+	}
+	return fmt.Sprintf("%s-ca-cert", cr.Spec.ElasticConfig.ClusterName)
+}
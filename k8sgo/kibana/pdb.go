@@ -0,0 +1,49 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8skibana
+
+import (
+	"k8s.io/apimachinery/pkg/util/intstr"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+	"logging-operator/k8sgo"
+)
+
+// CreateKibanaPodDisruptionBudget is a method to limit the Kibana pods which can be evicted at once
+func CreateKibanaPodDisruptionBudget(cr *loggingv1beta1.Kibana) error {
+	budget := cr.Spec.PodDisruptionBudget
+	if budget != nil && budget.Enabled != nil && !*budget.Enabled {
+		return k8sgo.DeletePodDisruptionBudget(cr.Namespace, cr.ObjectMeta.Name)
+	}
+	labels := map[string]string{
+		"app":     cr.ObjectMeta.Name,
+		"service": "kibana",
+	}
+	pdbParams := k8sgo.PodDisruptionBudgetParameters{
+		PDBMeta:   k8sgo.GenerateObjectMetaInformation(cr.ObjectMeta.Name, cr.Namespace, labels, k8sgo.GenerateAnnotations()),
+		OwnerDef:  k8sgo.KibanaAsOwner(cr),
+		Namespace: cr.Namespace,
+		Labels:    labels,
+	}
+	if budget != nil && (budget.MinAvailable != nil || budget.MaxUnavailable != nil) {
+		pdbParams.MinAvailable = budget.MinAvailable
+		pdbParams.MaxUnavailable = budget.MaxUnavailable
+	} else {
+		maxUnavailable := intstr.FromInt(1)
+		pdbParams.MaxUnavailable = &maxUnavailable
+	}
+	return k8sgo.CreateOrUpdatePodDisruptionBudget(pdbParams)
+}
@@ -80,6 +80,30 @@ func KibanaAsOwner(cr *loggingv1beta1.Kibana) metav1.OwnerReference {
 	}
 }
 
+// ElasticsearchUserAsOwner generates and returns object refernece
+func ElasticsearchUserAsOwner(cr *loggingv1beta1.ElasticsearchUser) metav1.OwnerReference {
+	trueVar := true
+	return metav1.OwnerReference{
+		APIVersion: cr.APIVersion,
+		Kind:       cr.Kind,
+		Name:       cr.Name,
+		UID:        cr.UID,
+		Controller: &trueVar,
+	}
+}
+
+// ElasticsearchAPIKeyAsOwner generates and returns object refernece
+func ElasticsearchAPIKeyAsOwner(cr *loggingv1beta1.ElasticsearchAPIKey) metav1.OwnerReference {
+	trueVar := true
+	return metav1.OwnerReference{
+		APIVersion: cr.APIVersion,
+		Kind:       cr.Kind,
+		Name:       cr.Name,
+		UID:        cr.UID,
+		Controller: &trueVar,
+	}
+}
+
 // LabelSelectors generates object for label selection
 func LabelSelectors(labels map[string]string) *metav1.LabelSelector {
 	return &metav1.LabelSelector{MatchLabels: labels}
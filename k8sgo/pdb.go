@@ -0,0 +1,167 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PodDisruptionBudgetParameters is a structure for pod disruption budget inputs
+type PodDisruptionBudgetParameters struct {
+	PDBMeta        metav1.ObjectMeta
+	OwnerDef       metav1.OwnerReference
+	Namespace      string
+	Labels         map[string]string
+	MinAvailable   *intstr.IntOrString
+	MaxUnavailable *intstr.IntOrString
+}
+
+// CreateOrUpdatePodDisruptionBudget method will create or update pod disruption budget
+func CreateOrUpdatePodDisruptionBudget(params PodDisruptionBudgetParameters) error {
+	logger := LogGenerator(params.PDBMeta.Name, params.Namespace, "PodDisruptionBudget")
+	// policy/v1 rejects budgets with both fields
+	if params.MinAvailable != nil && params.MaxUnavailable != nil {
+		return fmt.Errorf("pod disruption budget %s can only set one of minAvailable and maxUnavailable", params.PDBMeta.Name)
+	}
+	pdbDef := generatePodDisruptionBudgetDef(params)
+	storedPDB, err := getPodDisruptionBudget(params.Namespace, params.PDBMeta.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(pdbDef); err != nil {
+				logger.Error(err, "Unable to patch pod disruption budget with compare annotations")
+			}
+			return createPodDisruptionBudget(params.Namespace, pdbDef)
+		}
+		return err
+	}
+	return patchPodDisruptionBudget(storedPDB, pdbDef, params.Namespace)
+}
+
+// patchPodDisruptionBudget will patch Kubernetes pod disruption budget
+func patchPodDisruptionBudget(storedPDB *policyv1.PodDisruptionBudget, newPDB *policyv1.PodDisruptionBudget, namespace string) error {
+	logger := LogGenerator(storedPDB.Name, namespace, "PodDisruptionBudget")
+	newPDB.ResourceVersion = storedPDB.ResourceVersion
+	newPDB.CreationTimestamp = storedPDB.CreationTimestamp
+	newPDB.ManagedFields = storedPDB.ManagedFields
+
+	patchResult, err := patch.DefaultPatchMaker.Calculate(storedPDB, newPDB,
+		patch.IgnoreStatusFields(),
+		patch.IgnoreField("kind"),
+		patch.IgnoreField("apiVersion"),
+	)
+	if err != nil {
+		logger.Error(err, "Unable to patch pod disruption budget with comparison object")
+		return err
+	}
+	if !patchResult.IsEmpty() {
+		for key, value := range storedPDB.Annotations {
+			if _, present := newPDB.Annotations[key]; !present {
+				newPDB.Annotations[key] = value
+			}
+		}
+		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(newPDB); err != nil {
+			logger.Error(err, "Unable to patch pod disruption budget with comparison object")
+			return err
+		}
+		logger.Info("Syncing pod disruption budget with defined properties")
+		return updatePodDisruptionBudget(namespace, newPDB)
+	}
+	return nil
+}
+
+// createPodDisruptionBudget is a method to create pod disruption budget
+func createPodDisruptionBudget(namespace string, pdb *policyv1.PodDisruptionBudget) error {
+	logger := LogGenerator(pdb.Name, namespace, "PodDisruptionBudget")
+	_, err := GenerateK8sClient().PolicyV1().PodDisruptionBudgets(namespace).Create(context.TODO(), pdb, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(err, "PodDisruptionBudget creation is failed")
+		return err
+	}
+	logger.Info("PodDisruptionBudget creation is successful")
+	return nil
+}
+
+// updatePodDisruptionBudget is a method to update pod disruption budget
+func updatePodDisruptionBudget(namespace string, pdb *policyv1.PodDisruptionBudget) error {
+	logger := LogGenerator(pdb.Name, namespace, "PodDisruptionBudget")
+	_, err := GenerateK8sClient().PolicyV1().PodDisruptionBudgets(namespace).Update(context.TODO(), pdb, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(err, "PodDisruptionBudget updation is failed")
+		return err
+	}
+	logger.Info("PodDisruptionBudget updation is successful")
+	return nil
+}
+
+// DeletePodDisruptionBudget is a method to delete pod disruption budget in Kubernetes
+func DeletePodDisruptionBudget(namespace string, name string) error {
+	logger := LogGenerator(name, namespace, "PodDisruptionBudget")
+	err := GenerateK8sClient().PolicyV1().PodDisruptionBudgets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		logger.Error(err, "PodDisruptionBudget deletion failed")
+		return err
+	}
+	logger.Info("PodDisruptionBudget deletion was successful")
+	return nil
+}
+
+// ListPodDisruptionBudgets is a method to list the pod disruption budgets of a namespace in Kubernetes
+func ListPodDisruptionBudgets(namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	logger := LogGenerator(namespace, namespace, "PodDisruptionBudget")
+	pdbList, err := GenerateK8sClient().PolicyV1().PodDisruptionBudgets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Info("PodDisruptionBudget list action failed")
+		return nil, err
+	}
+	return pdbList.Items, nil
+}
+
+// getPodDisruptionBudget is a method to get pod disruption budget
+func getPodDisruptionBudget(namespace string, name string) (*policyv1.PodDisruptionBudget, error) {
+	logger := LogGenerator(name, namespace, "PodDisruptionBudget")
+	pdbInfo, err := GenerateK8sClient().PolicyV1().PodDisruptionBudgets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Info("PodDisruptionBudget get action is failed")
+		return nil, err
+	}
+	return pdbInfo, nil
+}
+
+// generatePodDisruptionBudgetDef is a method to generate pod disruption budget definition
+func generatePodDisruptionBudgetDef(params PodDisruptionBudgetParameters) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta:   GenerateMetaInformation("PodDisruptionBudget", "policy/v1"),
+		ObjectMeta: params.PDBMeta,
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:       LabelSelectors(params.Labels),
+			MinAvailable:   params.MinAvailable,
+			MaxUnavailable: params.MaxUnavailable,
+		},
+	}
+	AddOwnerRefToObject(pdb, params.OwnerDef)
+	return pdb
+}
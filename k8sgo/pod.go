@@ -0,0 +1,105 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sgo
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GetPod is a method to get pod in Kubernetes
+func GetPod(namespace string, name string) (*corev1.Pod, error) {
+	logger := LogGenerator(name, namespace, "Pod")
+	podInfo, err := GenerateK8sClient().CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Info("Pod get action failed")
+		return nil, err
+	}
+	logger.Info("Pod get action was successful")
+	return podInfo, nil
+}
+
+// ListPods is a method to list the pods matching the labels in Kubernetes
+func ListPods(namespace string, labels map[string]string) ([]corev1.Pod, error) {
+	logger := LogGenerator(namespace, namespace, "Pod")
+	selector := metav1.FormatLabelSelector(LabelSelectors(labels))
+	podList, err := GenerateK8sClient().CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		logger.Info("Pod list action failed")
+		return nil, err
+	}
+	return podList.Items, nil
+}
+
+// AnnotatePod is a method to add an annotation to a pod in Kubernetes
+func AnnotatePod(pod *corev1.Pod, key string, value string) error {
+	logger := LogGenerator(pod.Name, pod.Namespace, "Pod")
+	patchData, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{key: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = GenerateK8sClient().CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, patchData, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(err, "Pod annotation failed")
+		return err
+	}
+	logger.Info("Pod annotation was successful", "annotation", key)
+	return nil
+}
+
+// GetPodLogs is a method to get the last lines logged by a container of a pod in Kubernetes
+func GetPodLogs(namespace string, name string, container string, tailLines int64) (string, error) {
+	logger := LogGenerator(name, namespace, "Pod")
+	logs, err := GenerateK8sClient().CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{Container: container, TailLines: &tailLines}).DoRaw(context.TODO())
+	if err != nil {
+		logger.Info("Pod logs get action failed")
+		return "", err
+	}
+	return string(logs), nil
+}
+
+// GetNode is a method to get node in Kubernetes
+func GetNode(name string) (*corev1.Node, error) {
+	logger := LogGenerator(name, "", "Node")
+	nodeInfo, err := GenerateK8sClient().CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Info("Node get action failed")
+		return nil, err
+	}
+	return nodeInfo, nil
+}
+
+// IsPodReady is a method to check if pod is running and passes its readiness probe
+func IsPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
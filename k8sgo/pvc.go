@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sgo
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// GetPersistentVolumeClaim is a method to get persistent volume claim in Kubernetes
+func GetPersistentVolumeClaim(namespace string, name string) (*corev1.PersistentVolumeClaim, error) {
+	logger := LogGenerator(name, namespace, "PersistentVolumeClaim")
+	pvcInfo, err := GenerateK8sClient().CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Info("PersistentVolumeClaim get action failed")
+		return nil, err
+	}
+	return pvcInfo, nil
+}
+
+// ListPersistentVolumeClaims is a method to list the persistent volume claims of a namespace in Kubernetes
+func ListPersistentVolumeClaims(namespace string) ([]corev1.PersistentVolumeClaim, error) {
+	logger := LogGenerator(namespace, namespace, "PersistentVolumeClaim")
+	pvcList, err := GenerateK8sClient().CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Info("PersistentVolumeClaim list action failed")
+		return nil, err
+	}
+	return pvcList.Items, nil
+}
+
+// DeletePersistentVolumeClaim is a method to delete persistent volume claim in Kubernetes
+func DeletePersistentVolumeClaim(namespace string, name string) error {
+	logger := LogGenerator(name, namespace, "PersistentVolumeClaim")
+	err := GenerateK8sClient().CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "PersistentVolumeClaim deletion failed")
+		return err
+	}
+	logger.Info("PersistentVolumeClaim deletion was successful")
+	return nil
+}
+
+// ResizePersistentVolumeClaim is a method to raise the storage request of a persistent volume claim in Kubernetes
+func ResizePersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim, size resource.Quantity) error {
+	logger := LogGenerator(pvc.Name, pvc.Namespace, "PersistentVolumeClaim")
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+	_, err := GenerateK8sClient().CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(context.TODO(), pvc, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(err, "PersistentVolumeClaim resize failed")
+		return err
+	}
+	logger.Info("PersistentVolumeClaim resize was requested", "size", size.String())
+	return nil
+}
+
+// IsStorageClassExpandable is a method to check if volumes of a storage class can be expanded
+func IsStorageClassExpandable(name *string) (bool, error) {
+	storageClasses, err := GenerateK8sClient().StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, storageClass := range storageClasses.Items {
+		// claims without a storage class are provisioned by the default storage class
+		if (name != nil && storageClass.Name == *name) || (name == nil && storageClass.Annotations[defaultStorageClassAnnotation] == "true") {
+			return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+		}
+	}
+	return false, nil
+}
@@ -18,9 +18,14 @@ package k8sgo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // SecretsParameters is an interface for secret input
@@ -31,6 +36,7 @@ type SecretsParameters struct {
 	SecretsMeta metav1.ObjectMeta
 	SecretKey   string
 	SecretValue []byte
+	SecretData  map[string][]byte
 }
 
 // GenerateSecret is a method that will generate a secret interface
@@ -42,6 +48,9 @@ func GenerateSecret(params SecretsParameters) *corev1.Secret {
 			params.SecretKey: params.SecretValue,
 		},
 	}
+	if params.SecretData != nil {
+		secret.Data = params.SecretData
+	}
 	AddOwnerRefToObject(secret, params.OwnerDef)
 	return secret
 }
@@ -78,3 +87,108 @@ func GetElasticDBPassword(name, namespace string) string {
 	value := string(secretName.Data["password"])
 	return value
 }
+
+// UpdateSecret is a method to update Kubernetes secrets
+func UpdateSecret(namespace string, secret *corev1.Secret) error {
+	logger := LogGenerator(secret.Name, namespace, "Secret")
+	_, err := GenerateK8sClient().CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(err, "Secret updation is failed")
+		return err
+	}
+	logger.Info("Secret updation is successful")
+	return nil
+}
+
+// CreateOrUpdateSecret is a method to create secret or update it when it already exists
+func CreateOrUpdateSecret(namespace string, secret *corev1.Secret) error {
+	storedSecret, err := GetSecret(secret.Name, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return CreateSecret(namespace, secret)
+		}
+		return err
+	}
+	secret.ResourceVersion = storedSecret.ResourceVersion
+	return UpdateSecret(namespace, secret)
+}
+
+// DeleteSecret is a method to delete Kubernetes secrets
+func DeleteSecret(name, namespace string) error {
+	logger := LogGenerator(name, namespace, "Secret")
+	err := GenerateK8sClient().CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Secret deletion is failed")
+		return err
+	}
+	logger.Info("Secret deletion is successful")
+	return nil
+}
+
+// ReleaseSecret is a method to remove an owner from a secret so that it is kept when the owner is deleted
+func ReleaseSecret(name, namespace string, owner types.UID) error {
+	secret, err := GetSecret(name, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	var ownerReferences []metav1.OwnerReference
+	for _, ownerReference := range secret.OwnerReferences {
+		if ownerReference.UID != owner {
+			ownerReferences = append(ownerReferences, ownerReference)
+		}
+	}
+	if len(ownerReferences) == len(secret.OwnerReferences) {
+		return nil
+	}
+	secret.OwnerReferences = ownerReferences
+	return UpdateSecret(namespace, secret)
+}
+
+// IsSecretOwnedBy is a method to check if a secret carries the owner reference of an object
+func IsSecretOwnedBy(secret *corev1.Secret, owner types.UID) bool {
+	for _, ownerReference := range secret.OwnerReferences {
+		if ownerReference.UID == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteOwnedSecret is a method to delete a secret only if it carries the owner reference of an object
+func DeleteOwnedSecret(name, namespace string, owner types.UID) error {
+	secret, err := GetSecret(name, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !IsSecretOwnedBy(secret, owner) {
+		logger := LogGenerator(name, namespace, "Secret")
+		logger.Info("Secret is not owned by the object, keeping it")
+		return nil
+	}
+	return DeleteSecret(name, namespace)
+}
+
+// GetSecretHash is a method to calculate the checksum of secret data
+func GetSecretHash(name, namespace string) (string, error) {
+	secret, err := GetSecret(name, namespace)
+	if err != nil {
+		return "", err
+	}
+	var keys []string
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write(secret.Data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
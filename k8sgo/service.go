@@ -120,6 +120,18 @@ func updateService(namespace string, service *corev1.Service) error {
 	return nil
 }
 
+// DeleteService is a method to delete service in Kubernetes
+func DeleteService(namespace string, service string) error {
+	logger := LogGenerator(service, namespace, "Service")
+	err := GenerateK8sClient().CoreV1().Services(namespace).Delete(context.TODO(), service, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Service deletion is failed")
+		return err
+	}
+	logger.Info("Service deletion is successful")
+	return nil
+}
+
 // getService is a method to get service
 func getService(namespace string, service string) (*corev1.Service, error) {
 	logger := LogGenerator(service, namespace, "Service")
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 Opstree Solutions.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sgo
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	loggingv1beta1 "logging-operator/api/v1beta1"
+)
+
+var snapshotRepositoryResource = schema.GroupVersionResource{Group: loggingv1beta1.GroupVersion.Group, Version: loggingv1beta1.GroupVersion.Version, Resource: "snapshotrepositories"}
+
+// ListSnapshotRepositories is a method to list the snapshot repositories of a namespace
+func ListSnapshotRepositories(namespace string) ([]loggingv1beta1.SnapshotRepository, error) {
+	logger := LogGenerator(namespace, namespace, "SnapshotRepository")
+	repositoryList, err := GenerateK8sDynamicClient().Resource(snapshotRepositoryResource).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Info("SnapshotRepository list action failed")
+		return nil, err
+	}
+	var repositories loggingv1beta1.SnapshotRepositoryList
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(repositoryList.UnstructuredContent(), &repositories)
+	if err != nil {
+		return nil, err
+	}
+	return repositories.Items, nil
+}
+
+// GetSnapshotRepository is a method to get a snapshot repository in Kubernetes
+func GetSnapshotRepository(namespace string, name string) (*loggingv1beta1.SnapshotRepository, error) {
+	logger := LogGenerator(name, namespace, "SnapshotRepository")
+	repositoryInfo, err := GenerateK8sDynamicClient().Resource(snapshotRepositoryResource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Info("SnapshotRepository get action failed")
+		return nil, err
+	}
+	var repository loggingv1beta1.SnapshotRepository
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(repositoryInfo.UnstructuredContent(), &repository)
+	if err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
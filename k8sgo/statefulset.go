@@ -18,14 +18,14 @@ package k8sgo
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"encoding/json"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/iamabhishek-dubey/k8s-objectmatcher/patch"
 )
@@ -38,7 +38,9 @@ type StatefulSetParameters struct {
 	ContainerParams   ContainerParams
 	Labels            map[string]string
 	Annotations       map[string]string
+	PodAnnotations    map[string]string
 	Replicas          *int32
+	Partition         *int32
 	PVCParameters     PVCParameters
 	Affinity          *corev1.Affinity
 	NodeSelector      map[string]string
@@ -46,8 +48,18 @@ type StatefulSetParameters struct {
 	PriorityClassName *string
 	SecurityContext   *corev1.PodSecurityContext
 	ExtraVolumes      *[]corev1.Volume
-	ESPlugins         *[]string
-	ESKeystoreSecret  *string
+	// SysctlInitContainer raises vm.max_map_count with a privileged init container
+	SysctlInitContainer bool
+	ImagePullSecrets    []corev1.LocalObjectReference
+	// PluginInitContainers install the plugins before the keystore is created
+	PluginInitContainers []corev1.Container
+	// KeystoreInitContainers build the keystore before elasticsearch starts
+	KeystoreInitContainers []corev1.Container
+	// SidecarContainers run next to the main container
+	SidecarContainers []corev1.Container
+	// InitContainers are appended after the sysctl, plugin and keystore init containers
+	InitContainers            []corev1.Container
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
 }
 
 // PVCParameters is a struct to pass arguments for PVC
@@ -76,6 +88,11 @@ func CreateOrUpdateStateFul(params StatefulSetParameters) error {
 		}
 		return err
 	}
+	// a statefulset which is deleted with orphaned pods is created again by a later reconcile once it is gone
+	if storedStateful.DeletionTimestamp != nil {
+		logger.Info("StatefulSet is being deleted, waiting to create it again")
+		return nil
+	}
 	return patchStateFulSet(storedStateful, statefulSetDef, params.Namespace)
 }
 
@@ -139,6 +156,62 @@ func updateStateFulSet(namespace string, stateful *appsv1.StatefulSet) error {
 	return nil
 }
 
+// DeleteStateFulSetOrphan is a method to delete a statefulset in Kubernetes while keeping its pods running
+func DeleteStateFulSetOrphan(namespace string, name string) error {
+	logger := LogGenerator(name, namespace, "StatefulSet")
+	orphan := metav1.DeletePropagationOrphan
+	err := GenerateK8sClient().AppsV1().StatefulSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &orphan})
+	if err != nil {
+		logger.Error(err, "Statefulset deletion failed")
+		return err
+	}
+	logger.Info("Statefulset deleted with orphaned pods")
+	return nil
+}
+
+// ListStateFulSets is a method to list the statefulsets of a namespace in Kubernetes
+func ListStateFulSets(namespace string) ([]appsv1.StatefulSet, error) {
+	logger := LogGenerator(namespace, namespace, "StatefulSet")
+	statefulList, err := GenerateK8sClient().AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Info("Statefulset list action failed")
+		return nil, err
+	}
+	return statefulList.Items, nil
+}
+
+// ScaleStateFulSet is a method to change the replicas of a statefulset in Kubernetes
+func ScaleStateFulSet(namespace string, name string, replicas int32) error {
+	logger := LogGenerator(name, namespace, "StatefulSet")
+	patchData, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = GenerateK8sClient().AppsV1().StatefulSets(namespace).Patch(context.TODO(), name, types.MergePatchType, patchData, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(err, "Statefulset scaling failed")
+		return err
+	}
+	logger.Info("Statefulset successfully scaled", "replicas", replicas)
+	return nil
+}
+
+// DeleteStateFulSet is a method to delete a statefulset and its pods in Kubernetes
+func DeleteStateFulSet(namespace string, name string) error {
+	logger := LogGenerator(name, namespace, "StatefulSet")
+	err := GenerateK8sClient().AppsV1().StatefulSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Statefulset deletion failed")
+		return err
+	}
+	logger.Info("Statefulset deletion was successful")
+	return nil
+}
+
 // GetStateFulSet is a method to get statefulset in Kubernetes
 func GetStateFulSet(namespace string, stateful string) (*appsv1.StatefulSet, error) {
 	logger := LogGenerator(stateful, namespace, "StatefulSet")
@@ -151,6 +224,18 @@ func GetStateFulSet(namespace string, stateful string) (*appsv1.StatefulSet, err
 	return statefulInfo, err
 }
 
+// IsStateFulSetRolledOut is a method to check if all pods of statefulset run the latest revision
+func IsStateFulSetRolledOut(stateful *appsv1.StatefulSet) bool {
+	var replicas int32 = 1
+	if stateful.Spec.Replicas != nil {
+		replicas = *stateful.Spec.Replicas
+	}
+	return stateful.Status.ObservedGeneration >= stateful.Generation &&
+		stateful.Status.UpdatedReplicas == replicas &&
+		stateful.Status.ReadyReplicas == replicas &&
+		stateful.Status.CurrentRevision == stateful.Status.UpdateRevision
+}
+
 // generateStatefulSetDef is a method to generate statefulset definition
 func generateStatefulSetDef(params StatefulSetParameters) *appsv1.StatefulSet {
 	var serviceLink = true
@@ -166,10 +251,10 @@ func generateStatefulSetDef(params StatefulSetParameters) *appsv1.StatefulSet {
 			PodManagementPolicy: appsv1.ParallelPodManagement,
 			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
 				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
-				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{},
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: params.Partition},
 			},
 			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Labels: params.Labels},
+				ObjectMeta: metav1.ObjectMeta{Labels: params.Labels, Annotations: params.PodAnnotations},
 				Spec: corev1.PodSpec{
 					Containers:   generateContainerDef(params.ContainerParams),
 					NodeSelector: params.NodeSelector,
@@ -178,19 +263,24 @@ func generateStatefulSetDef(params StatefulSetParameters) *appsv1.StatefulSet {
 						FSGroup:   &fsGroup,
 						RunAsUser: &runasUser,
 					},
-					InitContainers:     []corev1.Container{getInitContainer(params.ContainerParams)},
 					EnableServiceLinks: &serviceLink,
+					ImagePullSecrets:   params.ImagePullSecrets,
 				},
 			},
 		},
 	}
 
-	if params.ESPlugins != nil {
-		statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, getPluginInitContainers(params))
+	if params.SecurityContext != nil {
+		statefulset.Spec.Template.Spec.SecurityContext = params.SecurityContext
 	}
-	if params.ESKeystoreSecret != nil {
-		statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, getKeystoreInitContainer(params))
+	if params.SysctlInitContainer {
+		statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, getInitContainer(params.ContainerParams))
 	}
+	statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, params.PluginInitContainers...)
+	statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, params.KeystoreInitContainers...)
+	statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, params.InitContainers...)
+	statefulset.Spec.Template.Spec.Containers = append(statefulset.Spec.Template.Spec.Containers, params.SidecarContainers...)
+	statefulset.Spec.Template.Spec.TopologySpreadConstraints = params.TopologySpreadConstraints
 	if params.ExtraVolumes != nil {
 		statefulset.Spec.Template.Spec.Volumes = *params.ExtraVolumes
 	}
@@ -227,52 +317,13 @@ func getInitContainer(params ContainerParams) corev1.Container {
 	var privileged = true
 	var runasUser int64 = 0
 	return corev1.Container{
-		Name:    "sysctl-init",
-		Image:   params.Image,
-		Command: []string{"sysctl", "-w", "vm.max_map_count=262144"},
+		Name:            "sysctl-init",
+		Image:           params.Image,
+		ImagePullPolicy: params.ImagePullPolicy,
+		Command:         []string{"sysctl", "-w", "vm.max_map_count=262144"},
 		SecurityContext: &corev1.SecurityContext{
 			Privileged: &privileged,
 			RunAsUser:  &runasUser,
 		},
 	}
 }
-
-// getPluginInitContainers is a method to create plugins init container
-func getPluginInitContainers(params StatefulSetParameters) corev1.Container {
-	shellCommand := []string{"sh", "-c"}
-	command := []string{"bin/elasticsearch-plugin install --batch"}
-	command = append(command, *params.ESPlugins...)
-
-	shellCommand = append(shellCommand, strings.Join(command, " "))
-	return corev1.Container{
-		Name:    "plugins",
-		Image:   params.ContainerParams.Image,
-		Command: shellCommand,
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      "plugin-volume",
-				MountPath: "/usr/share/elasticsearch/plugins",
-			},
-		},
-	}
-}
-
-// getKeystoreInitContainer is a method to create init container for keystore
-func getKeystoreInitContainer(params StatefulSetParameters) corev1.Container {
-	secretName := fmt.Sprintf("/tmp/keystoreSecrets/%s", *params.ESKeystoreSecret)
-	return corev1.Container{
-		Name:    "keystore",
-		Image:   params.ContainerParams.Image,
-		Command: []string{"bash", "-c", keyStoreCommand},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      "keystore-volume",
-				MountPath: "/tmp/keystore",
-			},
-			{
-				Name:      "keystore-secret",
-				MountPath: secretName,
-			},
-		},
-	}
-}
@@ -18,12 +18,15 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -33,6 +36,7 @@ import (
 
 	loggingv1beta1 "logging-operator/api/v1beta1"
 	"logging-operator/controllers"
+	"logging-operator/k8sgo"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -52,11 +56,20 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var imageDefaults k8sgo.ImageDefaults
+	var imagePullPolicy string
+	var imagePullSecrets string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&imageDefaults.Registry, "image-registry", "", "Registry which replaces the registry of the default images, e.g. an internal mirror.")
+	flag.StringVar(&imageDefaults.ElasticsearchImage, "elasticsearch-image", k8sgo.DefaultElasticsearchImage, "Default elasticsearch image, it is tagged with the version of the cluster.")
+	flag.StringVar(&imageDefaults.KibanaImage, "kibana-image", k8sgo.DefaultKibanaImage, "Default kibana image, it is tagged with the version of the cluster.")
+	flag.StringVar(&imageDefaults.FluentdImage, "fluentd-image", k8sgo.DefaultFluentdImage, "Default fluentd image.")
+	flag.StringVar(&imagePullPolicy, "image-pull-policy", "", "Default image pull policy of the managed workloads, one of Always, IfNotPresent or Never.")
+	flag.StringVar(&imagePullSecrets, "image-pull-secrets", "", "Comma separated list of default image pull secrets, they have to exist in the namespaces of the managed workloads.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -65,6 +78,20 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	switch corev1.PullPolicy(imagePullPolicy) {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		imageDefaults.PullPolicy = corev1.PullPolicy(imagePullPolicy)
+	default:
+		setupLog.Error(fmt.Errorf("image pull policy %s is not one of Always, IfNotPresent or Never", imagePullPolicy), "invalid image pull policy")
+		os.Exit(1)
+	}
+	for _, secretName := range strings.Split(imagePullSecrets, ",") {
+		if strings.TrimSpace(secretName) != "" {
+			imageDefaults.PullSecrets = append(imageDefaults.PullSecrets, corev1.LocalObjectReference{Name: strings.TrimSpace(secretName)})
+		}
+	}
+	k8sgo.SetImageDefaults(imageDefaults)
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
@@ -113,6 +140,41 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "IndexTemplate")
 		os.Exit(1)
 	}
+	if err = (&controllers.SnapshotRepositoryReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SnapshotRepository")
+		os.Exit(1)
+	}
+	if err = (&controllers.SnapshotPolicyReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SnapshotPolicy")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchRoleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchRole")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchUserReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchUser")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchAPIKeyReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchAPIKey")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {